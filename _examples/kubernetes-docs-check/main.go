@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform/builtin/providers/kubernetes"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/docsgen"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalf("Usage: %s <path-to-terraform-repo>", os.Args[0])
+	}
+	repoPath := os.Args[1]
+
+	p := kubernetes.Provider().(*schema.Provider)
+	exitCode := 0
+	for key, res := range p.ResourcesMap {
+		r := &docsgen.Resource{
+			ProviderKey:    "kubernetes",
+			ProviderName:   "Kubernetes",
+			ResourceKey:    key,
+			ResourceSchema: res,
+		}
+		path := filepath.Join(repoPath, r.DocsPath())
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		result, err := r.Check(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, p := range result.Problems {
+			fmt.Printf("%s: %s\n", path, p)
+		}
+		if result.ExitCode() != 0 {
+			exitCode = result.ExitCode()
+		}
+	}
+	os.Exit(exitCode)
+}
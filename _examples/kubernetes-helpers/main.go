@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
@@ -9,8 +10,12 @@ import (
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/converters"
 	"github.com/radeksimko/terraform-gen/helpergen"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	api "k8s.io/kubernetes/pkg/api/v1"
 )
 
@@ -26,41 +31,94 @@ func main() {
 			Obj:      api.PersistentVolumeSpec{},
 			Filename: "structure_persistent_volume_spec.go",
 		},
+		{
+			Obj:      api.PodSpec{},
+			Filename: "structure_pod_spec.go",
+		},
 	}
 
+	hg := &helpergen.HelperGenerator{
+		InputVarName:           "in",
+		OutputVarName:          "att",
+		InlineFieldFilterFunc:  inlineFilterFunc,
+		OutlineFieldFilterFunc: outlineFilterFunc,
+		Converters:             kubernetesConverters(),
+	}
+	session := helpergen.NewSession(hg)
 	for _, s := range schemas {
 		log.Printf("Generating %q...\n", s.Filename)
-		f, err := os.Create(s.Filename)
-		defer f.Close()
+		err := session.Add(s.Filename, s.Obj)
 		if err != nil {
 			log.Fatal(err)
 		}
+	}
 
-		hg := &helpergen.HelperGenerator{
-			InputVarName:           "in",
-			OutputVarName:          "att",
-			InlineFieldFilterFunc:  inlineFilterFunc,
-			OutlineFieldFilterFunc: outlineFilterFunc,
-		}
+	out := session.Output()
+	for _, s := range schemas {
+		writeHelpers(s.Filename, pkgName, out.Files[s.Filename])
+	}
+	// helpers used by more structs (e.g. volume sources)
+	writeHelpers("structure_shared.go", pkgName, out.Shared)
+}
 
-		flatteners := hg.FlattenersFromStruct(s.Obj)
-		expanders := hg.ExpandersFromStruct(s.Obj)
-
-		err = tpl.Execute(f, struct {
-			PkgName    string
-			Flatteners map[string]string
-			Expanders  map[string]string
-		}{
-			PkgName:    pkgName,
-			Flatteners: flatteners,
-			Expanders:  expanders,
-		})
-		if err != nil {
-			log.Fatal(err)
-		}
+func writeHelpers(filename, pkgName string, helpers map[string]string) {
+	existingSrc, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+	helpers, err = helpergen.PreserveKept(helpers, existingSrc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f, err := os.Create(filename)
+	defer f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = tpl.Execute(f, struct {
+		PkgName string
+		Helpers map[string]string
+	}{
+		PkgName: pkgName,
+		Helpers: helpers,
+	})
+	if err != nil {
+		log.Fatal(err)
 	}
 }
 
+// kubernetesConverters registers special Kubernetes types, which are strings in the schema
+func kubernetesConverters() *converters.Registry {
+	r := converters.NewBuiltinRegistry()
+	r.Register(metav1.Time{}, &converters.Converter{
+		SchemaType:      schema.TypeString,
+		ValidateRFC3339: true,
+		Expander:        "expandMetaV1Time(%s)",
+		Flattener:       "%s.Format(time.RFC3339)",
+		Helpers: []*converters.Helper{
+			{
+				FuncName:  "expandMetaV1Time",
+				Arguments: "v string",
+				Outputs:   "metav1.Time",
+				FuncBody:  "t, err := time.Parse(time.RFC3339, v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse time: %s\", err)\n}\nreturn metav1.NewTime(t)",
+			},
+		},
+	})
+	r.Register(resource.Quantity{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "resource.MustParse(%s)",
+		Flattener:  "%s.String()",
+	})
+	r.Register(intstr.IntOrString{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "intstr.Parse(%s)",
+		Flattener:  "%s.String()",
+	})
+	return r
+}
+
 func inlineFilterFunc(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
 	tag := sf.Tag
 	jsonTag := strings.Split(tag.Get("json"), ",")
@@ -153,13 +211,7 @@ func getSwaggerDocs(iface interface{}, sf *reflect.StructField, jsonName string)
 
 var tpl = template.Must(template.New("pod").Parse(`package {{.PkgName}}
 
-// Flatteners
-{{range $name, $definition := .Flatteners}}
-{{ $definition }}
-{{end}}
-
-// Expanders
-{{range $name, $definition := .Expanders}}
+{{range $name, $definition := .Helpers}}
 {{ $definition }}
 {{end}}
 `))
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/builtin/providers/kubernetes"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/schemagen"
+
+	api "k8s.io/kubernetes/pkg/api/v1"
+)
+
+func main() {
+	p := kubernetes.Provider().(*schema.Provider)
+	liveSpec := p.ResourcesMap["kubernetes_service"].Schema["spec"].Elem.(*schema.Resource)
+
+	sg := &schemagen.SchemaGenerator{DocsFunc: docsFunc, FilterFunc: filterFunc}
+	generatedSpec := sg.SchemaFromStruct(&api.ServiceSpec{})
+
+	changes := schemagen.DiffSchemas(liveSpec.Schema, generatedSpec)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+	if schemagen.HasBreakingChanges(changes) {
+		os.Exit(1)
+	}
+}
+
+func docsFunc(iface interface{}, sf *reflect.StructField) string {
+	return ""
+}
+
+func filterFunc(iface interface{}, sf *reflect.StructField, kind reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+	jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+	if jsonName == "-" {
+		return kind, false
+	}
+
+	docs, err := getSwaggerDocs(iface, sf, jsonName)
+	if err != nil {
+		return kind, true
+	}
+	if strings.Contains(docs, "Deprecated:") || strings.Contains(docs, "NOT YET IMPLEMENTED.") {
+		return kind, false
+	}
+
+	if strings.Contains(docs, "Read-only.") {
+		s.Computed = true
+	} else if strings.Contains(docs, "Required.") || strings.Contains(docs, "Required:") {
+		s.Required = true
+	} else {
+		s.Optional = true
+	}
+	if strings.Contains(docs, "Cannot be updated.") {
+		s.ForceNew = true
+	}
+
+	return kind, true
+}
+
+func getSwaggerDocs(iface interface{}, sf *reflect.StructField, jsonName string) (string, error) {
+	structType := reflect.TypeOf(iface)
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+
+	val := reflect.New(structType).Elem()
+	method := val.MethodByName("SwaggerDoc")
+	if method.IsValid() {
+		out := method.Call([]reflect.Value{})
+		m := out[0]
+		docs := m.MapIndex(reflect.ValueOf(jsonName))
+		if !docs.IsValid() {
+			docs = m.MapIndex(reflect.ValueOf(""))
+		}
+		return docs.String(), nil
+	}
+	return "", fmt.Errorf("Docs not found for %s -> %s (%s)", structType.Name(), sf.Name, sf.Type.String())
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
@@ -9,8 +10,13 @@ import (
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	"github.com/radeksimko/terraform-gen/converters"
 	"github.com/radeksimko/terraform-gen/schemagen"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	api "k8s.io/kubernetes/pkg/api/v1"
 )
 
@@ -32,15 +38,34 @@ func main() {
 
 	for _, s := range schemas {
 		log.Printf("Generating %q...\n", s.Filename)
+		existingSrc, err := ioutil.ReadFile(s.Filename)
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+
+		sg := &schemagen.SchemaGenerator{
+			DocsFunc:   docsFunc,
+			FilterFunc: filterFunc,
+			Converters: kubernetesConverters(),
+			Backend:    backends.PluginSDKv2,
+			// only one of members of these may be specified
+			Unions: map[reflect.Type]schemagen.UnionKind{
+				reflect.TypeOf(api.VolumeSource{}):           schemagen.AtMostOneOf,
+				reflect.TypeOf(api.PersistentVolumeSource{}): schemagen.AtMostOneOf,
+				reflect.TypeOf(api.Handler{}):                schemagen.AtMostOneOf,
+			},
+		}
+		fields, err := schemagen.PreserveKept(sg.FromStruct(s.Obj), existingSrc)
+		if err != nil {
+			log.Fatal(err)
+		}
+
 		f, err := os.Create(s.Filename)
 		defer f.Close()
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		sg := &schemagen.SchemaGenerator{DocsFunc: docsFunc, FilterFunc: filterFunc}
-		fields := sg.FromStruct(s.Obj)
-
 		err = podTemplate.Execute(f, struct {
 			PkgName      string
 			VariableName string
@@ -56,6 +81,36 @@ func main() {
 	}
 }
 
+// kubernetesConverters registers special Kubernetes types, which are strings in the schema
+func kubernetesConverters() *converters.Registry {
+	r := converters.NewBuiltinRegistry()
+	r.Register(metav1.Time{}, &converters.Converter{
+		SchemaType:      schema.TypeString,
+		ValidateRFC3339: true,
+		Expander:        "expandMetaV1Time(%s)",
+		Flattener:       "%s.Format(time.RFC3339)",
+		Helpers: []*converters.Helper{
+			{
+				FuncName:  "expandMetaV1Time",
+				Arguments: "v string",
+				Outputs:   "metav1.Time",
+				FuncBody:  "t, err := time.Parse(time.RFC3339, v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse time: %s\", err)\n}\nreturn metav1.NewTime(t)",
+			},
+		},
+	})
+	r.Register(resource.Quantity{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "resource.MustParse(%s)",
+		Flattener:  "%s.String()",
+	})
+	r.Register(intstr.IntOrString{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "intstr.Parse(%s)",
+		Flattener:  "%s.String()",
+	})
+	return r
+}
+
 func docsFunc(iface interface{}, sf *reflect.StructField) string {
 	tag := sf.Tag
 	jsonTag := strings.Split(tag.Get("json"), ",")
@@ -82,25 +137,9 @@ func filterFunc(iface interface{}, sf *reflect.StructField, kind reflect.Kind, s
 		t = t.Elem()
 	}
 
-	if sf.Type.String() == "v1.Time" {
-		log.Printf("Converting %s field to TypeString - %q\n", sf.Type.String(), sf.Name)
-		return reflect.String, true
-	}
-
-	if sf.Type.String() == "resource.Quantity" {
-		log.Printf("Converting %s field to TypeInt - %q\n", sf.Type.String(), sf.Name)
-		return reflect.Int, true
-	}
-
-	if sf.Type.String() == "intstr.IntOrString" {
-		log.Printf("Converting %s field to TypeInt - %q\n", sf.Type.String(), sf.Name)
-		return reflect.Int, true
-	}
-
 	// Pod
 	if (t.String() == "v1.Pod" && sf.Name == "Status") ||
-		(t.String() == "v1.Pod" && sf.Name == "PodSpec") ||
-		(t.String() == "v1.Volume" && sf.Name == "VolumeSource") {
+		(t.String() == "v1.Pod" && sf.Name == "PodSpec") {
 		log.Printf("Ignoring %q -> %q (will be implemented as data source)", t.String(), sf.Name)
 		return kind, false
 	}
@@ -170,7 +209,7 @@ func getSwaggerDocs(iface interface{}, sf *reflect.StructField, jsonName string)
 var podTemplate = template.Must(template.New("pod").Parse(`package {{.PkgName}}
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 var {{.VariableName}} = map[string]*schema.Schema{
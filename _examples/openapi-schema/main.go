@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"os"
+	"text/template"
+
+	"github.com/radeksimko/terraform-gen/schemagen"
+)
+
+// Usage: openapi-schema swagger.json io.k8s.api.core.v1.PodSpec > pod_spec_schema.go
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatalf("Usage: %s SPEC_PATH DEFINITION", os.Args[0])
+	}
+
+	spec, err := schemagen.LoadOpenAPISpec(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	g := &schemagen.OpenAPIGenerator{Spec: spec}
+	fields, err := g.FromDefinition(os.Args[2])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = schemaTemplate.Execute(os.Stdout, struct {
+		PkgName      string
+		VariableName string
+		Fields       map[string]string
+	}{
+		PkgName:      "kubernetes",
+		VariableName: "generatedSchema",
+		Fields:       fields,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+var schemaTemplate = template.Must(template.New("schema").Parse(`package {{.PkgName}}
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var {{.VariableName}} = map[string]*schema.Schema{
+{{range $name, $schema := .Fields}}
+	"{{ $name }}": {{ $schema }},{{end}}
+}
+`))
@@ -0,0 +1,107 @@
+// Package backends describes plugin SDKs targeted by code
+// generated by schemagen & helpergen.
+package backends
+
+import (
+	"reflect"
+)
+
+// Backend describes the plugin SDK which generated code targets
+type Backend struct {
+	Name string
+	// Framework is true for terraform-plugin-framework, which uses attributes,
+	// blocks & typed models instead of *schema.Schema and map[string]interface{}
+	Framework bool
+	// ContextFuncs is true for SDKs with CRUD functions taking context.Context
+	// and returning diag.Diagnostics (CreateContext, ReadContext etc.)
+	ContextFuncs bool
+	// SchemaImports are import paths which may be used by code generated by schemagen
+	// (unused ones can be pruned e.g. by goimports)
+	SchemaImports []string
+	// HelperImports are import paths which may be used by code generated by helpergen
+	HelperImports []string
+	// ResourceImports are import paths which may be used by resource scaffolding
+	// generated by schemagen, in addition to SchemaImports
+	ResourceImports []string
+	// ValidateJSONFunc is code of validation of JSON strings
+	ValidateJSONFunc string
+	// ValidateBase64Func is code of validation of base64 strings (empty if the SDK has none)
+	ValidateBase64Func string
+	// ValidateRFC3339Func is code of validation of RFC3339 timestamps
+	ValidateRFC3339Func string
+	// ExactlyOneOf is true for SDKs supporting ExactlyOneOf of schema fields
+	ExactlyOneOf bool
+}
+
+// HelperSchema targets helper/schema of Terraform core (up to 0.12)
+var HelperSchema = &Backend{
+	Name: "helper/schema",
+	SchemaImports: []string{
+		"github.com/hashicorp/terraform/helper/schema",
+		"github.com/hashicorp/terraform/helper/structure",
+		"github.com/hashicorp/terraform/helper/validation",
+	},
+	HelperImports:       []string{},
+	ResourceImports:     []string{},
+	ValidateJSONFunc:    "validation.ValidateJsonString",
+	ValidateRFC3339Func: "validation.ValidateRFC3339TimeString",
+}
+
+// PluginSDKv2 targets the standalone terraform-plugin-sdk/v2
+var PluginSDKv2 = &Backend{
+	Name:         "plugin-sdk/v2",
+	ContextFuncs: true,
+	SchemaImports: []string{
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation",
+	},
+	HelperImports: []string{},
+	ResourceImports: []string{
+		"context",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/diag",
+	},
+	ValidateJSONFunc:    "validation.StringIsJSON",
+	ValidateBase64Func:  "validation.StringIsBase64",
+	ValidateRFC3339Func: "validation.IsRFC3339Time",
+	ExactlyOneOf:        true,
+}
+
+// Framework targets terraform-plugin-framework
+var Framework = &Backend{
+	Name:      "framework",
+	Framework: true,
+	SchemaImports: []string{
+		"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator",
+		"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator",
+		"github.com/hashicorp/terraform-plugin-framework/attr",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64default",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault",
+		"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier",
+		"github.com/hashicorp/terraform-plugin-framework/schema/validator",
+		"github.com/hashicorp/terraform-plugin-framework/types",
+	},
+	HelperImports: []string{
+		"context",
+		"github.com/hashicorp/terraform-plugin-framework/diag",
+		"github.com/hashicorp/terraform-plugin-framework/types",
+	},
+}
+
+// ModelName returns name of the framework model of the given struct, e.g. PodSpecModel
+func ModelName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t.Name() + "Model"
+}
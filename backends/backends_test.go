@@ -0,0 +1,17 @@
+package backends
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModelName(t *testing.T) {
+	type PodSpec struct{}
+
+	for _, iface := range []interface{}{PodSpec{}, &PodSpec{}, []*PodSpec{}} {
+		given := ModelName(reflect.TypeOf(iface))
+		if given != "PodSpecModel" {
+			t.Fatalf("Expected PodSpecModel for %T, given: %q", iface, given)
+		}
+	}
+}
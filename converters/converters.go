@@ -0,0 +1,206 @@
+// Package converters holds the representation of special Go types
+// (e.g. time.Time) in the schema, shared by schemagen & helpergen.
+package converters
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// DurationValidateFunc is code of validation of duration strings, e.g. 1h30m
+const DurationValidateFunc = "validation.StringMatch(regexp.MustCompile(`^-?([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`), \"must be a duration, e.g. 1h30m\")"
+
+// Converter describes how a Go type is represented in the schema
+type Converter struct {
+	SchemaType schema.ValueType
+	// ValidateFunc is code of the validation function (optional)
+	ValidateFunc string
+	// ValidateRFC3339 validates values as RFC3339 timestamps instead,
+	// by function of the targeted SDK (see backends.Backend)
+	ValidateRFC3339 bool
+	// Expander is a format (with a single %s) of expression converting
+	// the schema value (e.g. in["created_at"].(string)) into the Go type
+	Expander string
+	// Flattener is a format (with a single %s) of expression converting
+	// the Go value (e.g. in.CreatedAt) into the schema value
+	Flattener string
+	// EmptyCondition is a format (with a single %s) of condition which is true
+	// if the Go value is set, guarding flatteners of optional fields, e.g. !%s.IsZero()
+	// (optional, pointers & numbers are compared with nil or 0)
+	EmptyCondition string
+	// Helpers are functions used by expressions above
+	Helpers []*Helper
+}
+
+// Helper is a function declaration to be generated alongside expanders/flatteners
+type Helper struct {
+	FuncName  string
+	Arguments string
+	Outputs   string
+	FuncBody  string
+	// FallibleFuncBody is body of the helper returning (Outputs, error),
+	// used instead of FuncBody when expanders return errors (optional)
+	FallibleFuncBody string
+}
+
+// SchemaGoType returns Go type of values of SchemaType in the raw config, e.g. int
+func (c *Converter) SchemaGoType() string {
+	switch c.SchemaType {
+	case schema.TypeInt:
+		return "int"
+	case schema.TypeFloat:
+		return "float64"
+	case schema.TypeBool:
+		return "bool"
+	}
+	return "string"
+}
+
+// Registry maps Go types to converters
+type Registry struct {
+	converters map[reflect.Type]*Converter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{converters: make(map[reflect.Type]*Converter, 0)}
+}
+
+// NewBuiltinRegistry returns registry with converters of time.Time, time.Duration
+// and protobuf well-known types (Duration, Timestamp & wrappers like StringValue)
+func NewBuiltinRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(time.Time{}, &Converter{
+		SchemaType:      schema.TypeString,
+		ValidateRFC3339: true,
+		Expander:        "expandRFC3339Time(%s)",
+		Flattener:       "%s.Format(time.RFC3339)",
+		EmptyCondition:  "!%s.IsZero()",
+		Helpers: []*Helper{
+			{
+				FuncName:         "expandRFC3339Time",
+				Arguments:        "v string",
+				Outputs:          "time.Time",
+				FuncBody:         "t, err := time.Parse(time.RFC3339, v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse time: %s\", err)\n}\nreturn t",
+				FallibleFuncBody: "return time.Parse(time.RFC3339, v)",
+			},
+		},
+	})
+	r.Register(time.Duration(0), &Converter{
+		SchemaType:     schema.TypeString,
+		ValidateFunc:   DurationValidateFunc,
+		Expander:       "expandTimeDuration(%s)",
+		Flattener:      "%s.String()",
+		EmptyCondition: "%s != 0",
+		Helpers: []*Helper{
+			{
+				FuncName:         "expandTimeDuration",
+				Arguments:        "v string",
+				Outputs:          "time.Duration",
+				FuncBody:         "d, err := time.ParseDuration(v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse duration: %s\", err)\n}\nreturn d",
+				FallibleFuncBody: "return time.ParseDuration(v)",
+			},
+		},
+	})
+	r.Register((*durationpb.Duration)(nil), &Converter{
+		SchemaType:   schema.TypeString,
+		ValidateFunc: DurationValidateFunc,
+		Expander:     "expandDuration(%s)",
+		Flattener:    "%s.AsDuration().String()",
+		Helpers: []*Helper{
+			{
+				FuncName:         "expandDuration",
+				Arguments:        "v string",
+				Outputs:          "*durationpb.Duration",
+				FuncBody:         "d, err := time.ParseDuration(v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse duration: %s\", err)\n}\nreturn durationpb.New(d)",
+				FallibleFuncBody: "d, err := time.ParseDuration(v)\nif err != nil {\nreturn nil, err\n}\nreturn durationpb.New(d), nil",
+			},
+		},
+	})
+	r.Register((*timestamppb.Timestamp)(nil), &Converter{
+		SchemaType:      schema.TypeString,
+		ValidateRFC3339: true,
+		Expander:        "expandTimestamp(%s)",
+		Flattener:       "%s.AsTime().Format(time.RFC3339)",
+		Helpers: []*Helper{
+			{
+				FuncName:         "expandTimestamp",
+				Arguments:        "v string",
+				Outputs:          "*timestamppb.Timestamp",
+				FuncBody:         "t, err := time.Parse(time.RFC3339, v)\nif err != nil {\nlog.Printf(\"ERROR: Unable to parse time: %s\", err)\n}\nreturn timestamppb.New(t)",
+				FallibleFuncBody: "t, err := time.Parse(time.RFC3339, v)\nif err != nil {\nreturn nil, err\n}\nreturn timestamppb.New(t), nil",
+			},
+		},
+	})
+	registerProtoWrappers(r)
+	return r
+}
+
+// registerProtoWrappers registers wrappers (e.g. StringValue) as primitive types,
+// the same way schemagen.ProtobufGenerator represents them
+func registerProtoWrappers(r *Registry) {
+	r.Register((*wrapperspb.StringValue)(nil), &Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "wrapperspb.String(%s)",
+		Flattener:  "%s.GetValue()",
+	})
+	r.Register((*wrapperspb.BytesValue)(nil), &Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "wrapperspb.Bytes([]byte(%s))",
+		Flattener:  "string(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.BoolValue)(nil), &Converter{
+		SchemaType: schema.TypeBool,
+		Expander:   "wrapperspb.Bool(%s)",
+		Flattener:  "%s.GetValue()",
+	})
+	r.Register((*wrapperspb.Int32Value)(nil), &Converter{
+		SchemaType: schema.TypeInt,
+		Expander:   "wrapperspb.Int32(int32(%s))",
+		Flattener:  "int(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.Int64Value)(nil), &Converter{
+		SchemaType: schema.TypeInt,
+		Expander:   "wrapperspb.Int64(int64(%s))",
+		Flattener:  "int(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.UInt32Value)(nil), &Converter{
+		SchemaType: schema.TypeInt,
+		Expander:   "wrapperspb.UInt32(uint32(%s))",
+		Flattener:  "int(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.UInt64Value)(nil), &Converter{
+		SchemaType: schema.TypeInt,
+		Expander:   "wrapperspb.UInt64(uint64(%s))",
+		Flattener:  "int(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.FloatValue)(nil), &Converter{
+		SchemaType: schema.TypeFloat,
+		Expander:   "wrapperspb.Float(float32(%s))",
+		Flattener:  "float64(%s.GetValue())",
+	})
+	r.Register((*wrapperspb.DoubleValue)(nil), &Converter{
+		SchemaType: schema.TypeFloat,
+		Expander:   "wrapperspb.Double(%s)",
+		Flattener:  "%s.GetValue()",
+	})
+}
+
+// Register registers converter for type of the given value, e.g. time.Time{}
+// or (*durationpb.Duration)(nil) for pointers
+func (r *Registry) Register(iface interface{}, c *Converter) {
+	r.converters[reflect.TypeOf(iface)] = c
+}
+
+// Lookup returns converter registered for exactly the given type
+func (r *Registry) Lookup(t reflect.Type) (*Converter, bool) {
+	if r == nil {
+		return nil, false
+	}
+	c, ok := r.converters[t]
+	return c, ok
+}
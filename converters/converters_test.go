@@ -0,0 +1,62 @@
+package converters
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRegistry_lookup(t *testing.T) {
+	type Quantity struct {
+		Value string
+	}
+
+	r := NewRegistry()
+	c := &Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "resource.MustParse(%s)",
+		Flattener:  "%s.String()",
+	}
+	r.Register(Quantity{}, c)
+
+	given, ok := r.Lookup(reflect.TypeOf(Quantity{}))
+	if !ok || given != c {
+		t.Fatalf("Expected converter to be registered for Quantity")
+	}
+	_, ok = r.Lookup(reflect.TypeOf(&Quantity{}))
+	if ok {
+		t.Fatalf("Expected no converter for *Quantity")
+	}
+}
+
+func TestNewBuiltinRegistry(t *testing.T) {
+	r := NewBuiltinRegistry()
+	for _, iface := range []interface{}{time.Time{}, time.Duration(0), &durationpb.Duration{}, &wrapperspb.StringValue{}} {
+		c, ok := r.Lookup(reflect.TypeOf(iface))
+		if !ok {
+			t.Fatalf("Expected builtin converter for %T", iface)
+		}
+		if c.SchemaType != schema.TypeString {
+			t.Fatalf("Expected %T to be TypeString, given: %s", iface, c.SchemaType)
+		}
+	}
+}
+
+func TestConverter_SchemaGoType(t *testing.T) {
+	cases := map[schema.ValueType]string{
+		schema.TypeString: "string",
+		schema.TypeInt:    "int",
+		schema.TypeFloat:  "float64",
+		schema.TypeBool:   "bool",
+	}
+	for st, expected := range cases {
+		c := &Converter{SchemaType: st}
+		if c.SchemaGoType() != expected {
+			t.Fatalf("Expected %q for %s, given: %q", expected, st, c.SchemaGoType())
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package docsgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func isArgument(s *schema.Schema) bool {
+	return s.Required || s.Optional
+}
+
+// isAttribute is true for all computed fields, including Optional+Computed
+// which are documented both as argument and attribute
+func isAttribute(s *schema.Schema) bool {
+	return s.Computed
+}
+
+// argumentAnnotation returns the parenthesized prefix of an argument
+// e.g. (Required) or (Optional, Sensitive)
+func argumentAnnotation(s *schema.Schema) string {
+	flags := []string{"Optional"}
+	if s.Required {
+		flags = []string{"Required"}
+	}
+	if s.Sensitive {
+		flags = append(flags, "Sensitive")
+	}
+	return "(" + strings.Join(flags, ", ") + ")"
+}
+
+// attributeAnnotation returns the prefix of an attribute (including trailing space)
+// or empty string if there is nothing worth highlighting
+func attributeAnnotation(s *schema.Schema) string {
+	if s.Sensitive {
+		return "(Sensitive) "
+	}
+	return ""
+}
+
+// registryAnnotation returns the parenthesized type & flags used by Registry docs
+// e.g. (String, Sensitive), deprecation is left to notes which include the message
+func registryAnnotation(s *schema.Schema) string {
+	flags := []string{typeAnnotation(s)}
+	if s.Sensitive {
+		flags = append(flags, "Sensitive")
+	}
+	return "(" + strings.Join(flags, ", ") + ")"
+}
+
+// argumentNotes returns sentences (each with leading space) to be appended
+// to the description of an argument, describing constraints & behaviour
+func argumentNotes(s *schema.Schema) string {
+	return argumentNotesWithItems(s, true)
+}
+
+func argumentNotesWithItems(s *schema.Schema, withItems bool) string {
+	notes := ""
+	if len(s.ConflictsWith) > 0 {
+		keys := make([]string, len(s.ConflictsWith), len(s.ConflictsWith))
+		for i, k := range s.ConflictsWith {
+			keys[i] = "`" + k + "`"
+		}
+		notes += fmt.Sprintf(" Conflicts with %s.", strings.Join(keys, ", "))
+	}
+	if withItems {
+		notes += itemsConstraint(s.MinItems, s.MaxItems)
+	}
+	if s.Default != nil {
+		notes += fmt.Sprintf(" Defaults to `%v`.", s.Default)
+	}
+	if s.ForceNew {
+		notes += " Changing this forces a new resource."
+	}
+	return notes + attributeNotes(s)
+}
+
+// attributeNotes returns sentences (each with leading space) to be appended
+// to the description of an attribute
+func attributeNotes(s *schema.Schema) string {
+	notes := ""
+	if s.Deprecated != "" {
+		notes += " **Deprecated**: " + s.Deprecated
+	}
+	if s.Removed != "" {
+		notes += " **Removed**: " + s.Removed
+	}
+	return notes
+}
+
+// registryNotes returns notes for either an argument or an attribute,
+// leaving out constraints which are already part of registryAnnotation
+func registryNotes(s *schema.Schema) string {
+	if isArgument(s) {
+		return argumentNotesWithItems(s, !isBlock(s))
+	}
+	return attributeNotes(s)
+}
+
+func itemsConstraint(min, max int) string {
+	switch {
+	case min > 0 && min == max:
+		return fmt.Sprintf(" Must have exactly %d %s.", min, pluralizeItems(min))
+	case min > 0 && max > 0:
+		return fmt.Sprintf(" Must have between %d and %d items.", min, max)
+	case min > 0:
+		return fmt.Sprintf(" Must have at least %d %s.", min, pluralizeItems(min))
+	case max > 0:
+		return fmt.Sprintf(" Must have at most %d %s.", max, pluralizeItems(max))
+	}
+	return ""
+}
+
+func pluralizeItems(n int) string {
+	if n == 1 {
+		return "item"
+	}
+	return "items"
+}
@@ -0,0 +1,60 @@
+package docsgen
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestItemsConstraint(t *testing.T) {
+	testCases := []struct {
+		Min, Max int
+		Expected string
+	}{
+		{0, 0, ""},
+		{1, 1, " Must have exactly 1 item."},
+		{2, 2, " Must have exactly 2 items."},
+		{1, 3, " Must have between 1 and 3 items."},
+		{1, 0, " Must have at least 1 item."},
+		{0, 1, " Must have at most 1 item."},
+		{0, 4, " Must have at most 4 items."},
+	}
+
+	for _, tc := range testCases {
+		constraint := itemsConstraint(tc.Min, tc.Max)
+		if constraint != tc.Expected {
+			t.Fatalf("Expected %q for min=%d max=%d, given: %q", tc.Expected, tc.Min, tc.Max, constraint)
+		}
+	}
+}
+
+func TestRegistryAnnotation(t *testing.T) {
+	testCases := []struct {
+		Schema   *schema.Schema
+		Expected string
+	}{
+		{
+			&schema.Schema{Type: schema.TypeString},
+			"(String)",
+		},
+		{
+			&schema.Schema{Type: schema.TypeString, Sensitive: true, Deprecated: "Gone"},
+			"(String, Sensitive)",
+		},
+		{
+			&schema.Schema{Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeInt}},
+			"(Map of Number)",
+		},
+		{
+			&schema.Schema{Type: schema.TypeSet, MinItems: 1, MaxItems: 2, Elem: &schema.Resource{}},
+			"(Block Set, Min: 1, Max: 2)",
+		},
+	}
+
+	for _, tc := range testCases {
+		annotation := registryAnnotation(tc.Schema)
+		if annotation != tc.Expected {
+			t.Fatalf("Expected %q, given: %q", tc.Expected, annotation)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package docsgen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type ProblemKind string
+
+const (
+	// ProblemMissing means the field exists in the schema, but is not documented
+	ProblemMissing ProblemKind = "missing"
+	// ProblemExtra means the field is documented, but doesn't exist in the schema
+	ProblemExtra ProblemKind = "extra"
+	// ProblemMisflagged means the field is documented with wrong flags
+	// (e.g. Required vs Optional, argument vs attribute)
+	ProblemMisflagged ProblemKind = "misflagged"
+)
+
+type Problem struct {
+	Kind ProblemKind
+	// Block is name of the nested block, empty for top-level fields
+	Block   string
+	Field   string
+	Message string
+}
+
+func (p *Problem) String() string {
+	name := p.Field
+	if p.Block != "" {
+		name = p.Block + "." + p.Field
+	}
+	return fmt.Sprintf("%s: %s (%s)", name, p.Message, p.Kind)
+}
+
+type CheckResult struct {
+	Problems []*Problem
+}
+
+// ExitCode returns code suitable for CI, i.e. non-zero if docs drifted from the schema
+func (cr *CheckResult) ExitCode() int {
+	if len(cr.Problems) > 0 {
+		return 1
+	}
+	return 0
+}
+
+const (
+	sectionNone = iota
+	sectionArguments
+	sectionAttributes
+)
+
+type documentedField struct {
+	Block     string
+	Name      string
+	Section   int
+	Required  bool
+	Optional  bool
+	IsFlagged bool
+}
+
+// Check parses existing markdown docs of the resource (either of the supported profiles)
+// and reports arguments & attributes which are missing, extra or misflagged
+// in comparison with the ResourceSchema
+func (r *Resource) Check(markdown io.Reader) (*CheckResult, error) {
+	documented, err := parseDocumentedFields(markdown)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := r.resourceDocsFromSchema(r.ResourceSchema)
+	result := &CheckResult{Problems: make([]*Problem, 0)}
+
+	blocks := map[string][]*Field{"": rd.Fields}
+	for _, nb := range rd.NestedFields {
+		blocks[nb.Name] = nb.Fields
+	}
+	blockNames := make([]string, 0, len(blocks))
+	for name := range blocks {
+		blockNames = append(blockNames, name)
+	}
+	sort.Strings(blockNames)
+
+	for _, block := range blockNames {
+		for _, f := range blocks[block] {
+			result.Problems = append(result.Problems, checkField(block, f, documented)...)
+		}
+	}
+
+	for _, df := range documented {
+		s, ok := schemaForDocumentedField(df, blocks)
+		if !ok && !isImplicitAttribute(df) {
+			result.Problems = append(result.Problems, &Problem{
+				Kind:    ProblemExtra,
+				Block:   df.Block,
+				Field:   df.Name,
+				Message: fmt.Sprintf("documented as %s, but not found in the schema", sectionName(df.Section)),
+			})
+			continue
+		}
+		if ok && df.Section == sectionArguments && !isArgument(s) {
+			result.Problems = append(result.Problems, &Problem{
+				Kind:    ProblemMisflagged,
+				Block:   df.Block,
+				Field:   df.Name,
+				Message: "documented as argument, but it is computed-only attribute",
+			})
+		}
+		if ok && df.Section == sectionAttributes && !isAttribute(s) {
+			result.Problems = append(result.Problems, &Problem{
+				Kind:    ProblemMisflagged,
+				Block:   df.Block,
+				Field:   df.Name,
+				Message: "documented as attribute, but it is not computed",
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func checkField(block string, f *Field, documented []*documentedField) []*Problem {
+	problems := make([]*Problem, 0)
+
+	if isArgument(f.Schema) {
+		df, ok := findDocumentedField(documented, block, f.Name, sectionArguments)
+		if !ok {
+			problems = append(problems, &Problem{
+				Kind:    ProblemMissing,
+				Block:   block,
+				Field:   f.Name,
+				Message: "argument is not documented",
+			})
+		} else if df.IsFlagged && (df.Required != f.Schema.Required || df.Optional != f.Schema.Optional) {
+			problems = append(problems, &Problem{
+				Kind:  ProblemMisflagged,
+				Block: block,
+				Field: f.Name,
+				Message: fmt.Sprintf("documented as %s, but schema says %s",
+					requiredOrOptional(df.Required), requiredOrOptional(f.Schema.Required)),
+			})
+		}
+	}
+
+	// Optional+Computed fields are commonly documented as arguments only
+	if f.Schema.Computed && !f.Schema.Optional {
+		if _, ok := findDocumentedField(documented, block, f.Name, sectionAttributes); !ok {
+			problems = append(problems, &Problem{
+				Kind:    ProblemMissing,
+				Block:   block,
+				Field:   f.Name,
+				Message: "attribute is not documented",
+			})
+		}
+	}
+
+	return problems
+}
+
+func findDocumentedField(documented []*documentedField, block, name string, section int) (*documentedField, bool) {
+	for _, df := range documented {
+		if df.Block == block && df.Name == name && df.Section == section {
+			return df, true
+		}
+	}
+	return nil, false
+}
+
+func schemaForDocumentedField(df *documentedField, blocks map[string][]*Field) (*schema.Schema, bool) {
+	for _, f := range blocks[df.Block] {
+		if f.Name == df.Name {
+			return f.Schema, true
+		}
+	}
+	return nil, false
+}
+
+// isImplicitAttribute is true for attributes which are typically documented,
+// but never declared in the schema
+func isImplicitAttribute(df *documentedField) bool {
+	return df.Block == "" && df.Section == sectionAttributes && df.Name == "id"
+}
+
+func requiredOrOptional(required bool) string {
+	if required {
+		return "Required"
+	}
+	return "Optional"
+}
+
+func sectionName(section int) string {
+	if section == sectionAttributes {
+		return "attribute"
+	}
+	return "argument"
+}
+
+var (
+	// e.g. * `name` - (Required) Description or - `name` (String) Description
+	documentedFieldRegexp = regexp.MustCompile("^\\s*[*-]\\s+`([^`]+)`(.*)$")
+	legacyFlagRegexp      = regexp.MustCompile(`^\s*-\s*\((Required|Optional)\b`)
+	nestedBlockRegexp     = regexp.MustCompile("^#{3,4}\\s+(?:Nested Schema for\\s+)?`([^`]+)`")
+)
+
+func parseDocumentedFields(markdown io.Reader) ([]*documentedField, error) {
+	fields := make([]*documentedField, 0)
+	block, section := "", sectionNone
+	// Nested blocks are only recognised within sections describing fields
+	inFieldsSection := false
+	// Registry profile carries the flag in the group title instead of each line
+	groupRequired, groupOptional := false, false
+	inCodeBlock := false
+
+	scanner := bufio.NewScanner(markdown)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " ")
+
+		if strings.HasPrefix(line, "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			continue
+		}
+
+		switch line {
+		case "## Argument Reference", "## Schema":
+			block, section, inFieldsSection = "", sectionArguments, true
+			groupRequired, groupOptional = false, false
+			continue
+		case "## Attributes Reference", "## Attribute Reference":
+			block, section, inFieldsSection = "", sectionAttributes, true
+			continue
+		case "## Nested Blocks":
+			block, section, inFieldsSection = "", sectionNone, true
+			continue
+		case "#### Arguments":
+			section = sectionArguments
+			continue
+		case "#### Attributes":
+			section = sectionAttributes
+			continue
+		case "### Required", "Required:":
+			section, groupRequired, groupOptional = sectionArguments, true, false
+			continue
+		case "### Optional", "Optional:":
+			section, groupRequired, groupOptional = sectionArguments, false, true
+			continue
+		case "### Read-Only", "Read-Only:":
+			section, groupRequired, groupOptional = sectionAttributes, false, false
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			block, section, inFieldsSection = "", sectionNone, false
+			continue
+		}
+		if m := nestedBlockRegexp.FindStringSubmatch(line); m != nil && inFieldsSection {
+			block, section = m[1], sectionArguments
+			groupRequired, groupOptional = false, false
+			continue
+		}
+
+		if section == sectionNone {
+			continue
+		}
+		m := documentedFieldRegexp.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		df := &documentedField{
+			Block:   block,
+			Name:    m[1],
+			Section: section,
+		}
+		if section == sectionArguments {
+			if fm := legacyFlagRegexp.FindStringSubmatch(m[2]); fm != nil {
+				df.IsFlagged = true
+				df.Required = fm[1] == "Required"
+				df.Optional = fm[1] == "Optional"
+			} else if groupRequired || groupOptional {
+				df.IsFlagged = true
+				df.Required = groupRequired
+				df.Optional = groupOptional
+			}
+		}
+		fields = append(fields, df)
+	}
+
+	return fields, scanner.Err()
+}
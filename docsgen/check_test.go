@@ -0,0 +1,148 @@
+package docsgen
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var checkTestSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"metadata": {
+			Type:     schema.TypeList,
+			Required: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"uid": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"my_int": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"zone": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"computed_field": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	},
+}
+
+func TestCheck_generated(t *testing.T) {
+	for _, profile := range []*Profile{LegacyWebsiteProfile, RegistryProfile} {
+		r := &Resource{
+			ProviderKey:    "cattle",
+			ProviderName:   "Cattle",
+			ResourceKey:    "cattle_cow",
+			ResourceSlug:   "cattle-cow",
+			ResourceSchema: checkTestSchema,
+			Profile:        profile,
+		}
+		buf := bytes.NewBuffer([]byte{})
+		err := r.GenerateResourceMarkdown(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := r.Check(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Problems) > 0 {
+			t.Fatalf("Expected no problems for %s profile, given: %s", profile.Name, result.Problems)
+		}
+		if result.ExitCode() != 0 {
+			t.Fatalf("Expected zero exit code for %s profile, given: %d", profile.Name, result.ExitCode())
+		}
+	}
+}
+
+func TestCheck_drifted(t *testing.T) {
+	markdown := `---
+layout: "cattle"
+---
+
+# cattle_cow
+
+Hand-written description.
+
+## Example Usage
+
+` + "```" + `
+* ` + "`in_code_block`" + ` - (Required) Never parsed.
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`metadata`" + ` - (Required) Standard metadata.
+* ` + "`my_int`" + ` - (Required) Sample integer.
+* ` + "`removed_field`" + ` - (Optional) No longer exists.
+* ` + "`computed_field`" + ` - (Optional) Yada yada yada
+
+### ` + "`metadata`" + `
+
+#### Arguments
+
+* ` + "`name`" + ` - (Required) Name.
+
+#### Attributes
+
+* ` + "`uid`" + ` - Unique ID.
+
+## Attributes Reference
+
+* ` + "`id`" + ` - ID of the cow.
+* ` + "`my_int`" + ` - Sample integer.
+
+## Import
+
+* ` + "`ignored`" + ` - Import section is not parsed.
+`
+
+	r := &Resource{
+		ProviderKey:    "cattle",
+		ResourceKey:    "cattle_cow",
+		ResourceSchema: checkTestSchema,
+	}
+	result, err := r.Check(strings.NewReader(markdown))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := make([]string, len(result.Problems), len(result.Problems))
+	for i, p := range result.Problems {
+		problems[i] = p.String()
+	}
+	expectedProblems := []string{
+		"computed_field: attribute is not documented (missing)",
+		"my_int: documented as Required, but schema says Optional (misflagged)",
+		"zone: argument is not documented (missing)",
+		"removed_field: documented as argument, but not found in the schema (extra)",
+		"computed_field: documented as argument, but it is computed-only attribute (misflagged)",
+		"my_int: documented as attribute, but it is not computed (misflagged)",
+	}
+	if !reflect.DeepEqual(problems, expectedProblems) {
+		t.Fatalf("Expected: %q\n\nGiven: %q", expectedProblems, problems)
+	}
+	if result.ExitCode() != 1 {
+		t.Fatalf("Expected non-zero exit code, given: %d", result.ExitCode())
+	}
+}
@@ -16,31 +16,77 @@ type Resource struct {
 	ResourceKey    string
 	ResourceSlug   string
 	ResourceSchema *schema.Resource
+
+	// Subcategory is only rendered by profiles which support it (e.g. Registry)
+	Subcategory string
+
+	// Profile decides how the docs are laid out, defaults to LegacyWebsiteProfile
+	Profile *Profile
+
+	// FieldOrder decides the order of fields in all sections, defaults to AlphabeticalOrder
+	FieldOrder FieldOrderFunc
 }
 
 func (r *Resource) GenerateResourceMarkdown(wr io.Writer) error {
-	rd := r.resourceDocsFromSchema(r.ResourceSchema, nil, false)
-	return resourceDocsTemplate.Execute(wr, rd)
+	rd := r.resourceDocsFromSchema(r.ResourceSchema)
+	return r.profile().Template.Execute(wr, rd)
 }
 
-func (r *Resource) resourceDocsFromSchema(res *schema.Resource, docs *ResourceDocs, isNested bool) *ResourceDocs {
-	if docs == nil {
-		docs = &ResourceDocs{
-			ProviderKey:        r.ProviderKey,
-			ProviderName:       r.ProviderName,
-			ResourceKey:        r.ResourceKey,
-			ResourceSlug:       r.ResourceSlug,
-			MarkdownHeaderFunc: markdownHeader,
-			Fields:             make(map[string]*schema.Schema),
-			NestedFields:       make(map[string]map[string]*schema.Schema),
-		}
+// DocsPath returns path of the markdown file (relative to the provider repository root)
+// where the docs are expected to live for the selected profile
+func (r *Resource) DocsPath() string {
+	return r.profile().PathFunc(r)
+}
+
+func (r *Resource) profile() *Profile {
+	if r.Profile == nil {
+		return LegacyWebsiteProfile
 	}
+	return r.Profile
+}
 
+func (r *Resource) fieldOrder() FieldOrderFunc {
+	if r.FieldOrder == nil {
+		return AlphabeticalOrder
+	}
+	return r.FieldOrder
+}
+
+func (r *Resource) resourceDocsFromSchema(res *schema.Resource) *ResourceDocs {
+	fields := make(map[string]*schema.Schema)
+	nestedFields := make(map[string]*schema.Schema)
+	collectFields(res, fields, nestedFields, false)
+
+	less := r.fieldOrder()
+	docs := &ResourceDocs{
+		ProviderKey:        r.ProviderKey,
+		ProviderName:       r.ProviderName,
+		ResourceKey:        r.ResourceKey,
+		ResourceSlug:       r.ResourceSlug,
+		Subcategory:        r.Subcategory,
+		MarkdownHeaderFunc: markdownHeader,
+		Fields:             sortFields(fields, less),
+		NestedFields:       make([]*NestedBlock, 0),
+	}
+
+	// Blocks are ordered the same way as the fields they belong to
+	for _, f := range sortFields(nestedFields, less) {
+		elem := f.Schema.Elem.(*schema.Resource)
+		docs.NestedFields = append(docs.NestedFields, &NestedBlock{
+			Name:   f.Name,
+			Fields: sortFields(elem.Schema, less),
+		})
+	}
+
+	return docs
+}
+
+func collectFields(res *schema.Resource, fields, nestedFields map[string]*schema.Schema, isNested bool) {
 	for name, s := range res.Schema {
 		if v, isResource := s.Elem.(*schema.Resource); isResource {
-			docs.NestedFields[name] = v.Schema
+			nestedFields[name] = s
 			log.Printf("Processing nested field: %q", name)
-			r.resourceDocsFromSchema(v, docs, true)
+			collectFields(v, fields, nestedFields, true)
 		}
 		if _, isSchema := s.Elem.(*schema.Schema); isSchema {
 			log.Printf("Nested Schema is not implemented (yet) - SKIPPING %q", name)
@@ -48,11 +94,9 @@ func (r *Resource) resourceDocsFromSchema(res *schema.Resource, docs *ResourceDo
 
 		if !isNested {
 			log.Printf("Processing primitive field: %q", name)
-			docs.Fields[name] = s
+			fields[name] = s
 		}
 	}
-
-	return docs
 }
 
 func markdownHeader(header string) string {
@@ -64,14 +108,25 @@ type ResourceDocs struct {
 	ProviderName string
 	ResourceKey  string
 	ResourceSlug string
+	Subcategory  string
 
-	Fields       map[string]*schema.Schema
-	NestedFields map[string]map[string]*schema.Schema
+	Fields       []*Field
+	NestedFields []*NestedBlock
 
 	MarkdownHeaderFunc func(s string) string
 }
 
-var resourceDocsTemplate = template.Must(template.New("resource-docs").Parse(`
+type Field struct {
+	Name   string
+	Schema *schema.Schema
+}
+
+type NestedBlock struct {
+	Name   string
+	Fields []*Field
+}
+
+var resourceDocsTemplate = template.Must(template.New("resource-docs").Funcs(templateFuncs).Parse(`
 ---
 layout: "{{.ProviderKey}}"
 page_title: "{{.ProviderName}}: {{.ResourceKey}}"
@@ -96,26 +151,26 @@ resource "{{.ResourceKey}}" "example" {
 ## Argument Reference
 
 The following arguments are supported:
-{{range $key, $schema := .Fields}}{{if or $schema.Optional $schema.Required }}
-* ` + "`{{ $key }}`" + ` - {{if $schema.Required}}(Required){{else}}(Optional){{end}} {{ $schema.Description }}
+{{range .Fields}}{{if isArgument .Schema}}
+* ` + "`{{ .Name }}`" + ` - {{argumentAnnotation .Schema}} {{ .Schema.Description }}{{argumentNotes .Schema}}
 {{- end}}{{end}}
 
 {{- if gt (len .NestedFields) 0}}
 
 ## Nested Blocks
-{{- range $fieldName, $nestedFields := .NestedFields}}
+{{- range .NestedFields}}
 
-### ` + "`{{ $fieldName }}`" + `
+### ` + "`{{ .Name }}`" + `
 
 #### Arguments
-{{range $key, $schema := $nestedFields}}{{if or $schema.Optional $schema.Required }}
-* ` + "`{{ $key }}`" + ` - {{if $schema.Required}}(Required){{else}}(Optional){{end}} {{ $schema.Description }}
+{{range .Fields}}{{if isArgument .Schema}}
+* ` + "`{{ .Name }}`" + ` - {{argumentAnnotation .Schema}} {{ .Schema.Description }}{{argumentNotes .Schema}}
 {{- end}}{{- end}}
 
 #### Attributes
 
-{{range $key, $schema := $nestedFields}}{{if and $schema.Computed (not $schema.Optional)}}
-* ` + "`{{ $key }}`" + ` - {{ $schema.Description }}
+{{range .Fields}}{{if isAttribute .Schema}}
+* ` + "`{{ .Name }}`" + ` - {{attributeAnnotation .Schema}}{{ .Schema.Description }}{{attributeNotes .Schema}}
 {{- end}}{{- end -}}
 
 {{end}}
@@ -125,9 +180,10 @@ The following arguments are supported:
 
 In addition to the arguments listed above, the following computed attributes are
 exported:
-{{range $key, $schema := .Fields}}{{if and $schema.Computed (not $schema.Optional)}}
-* ` + "`{{ $key }}`" + ` - {{ $schema.Description }}
-{{end}}{{end}}
+{{range .Fields}}{{if isAttribute .Schema}}
+* ` + "`{{ .Name }}`" + ` - {{attributeAnnotation .Schema}}{{ .Schema.Description }}{{attributeNotes .Schema}}
+{{- end}}{{end}}
+
 ## Import
 
 {{.ResourceKey}} can be imported using the , e.g.
@@ -209,6 +209,89 @@ func TestGenerateResourceMarkdown_doubleNested(t *testing.T) {
 	}
 }
 
+func TestGenerateResourceMarkdown_flags(t *testing.T) {
+	resource := schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Name of the cow.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"password": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Password for milking.",
+				Optional:      true,
+				Sensitive:     true,
+				ConflictsWith: []string{"token"},
+			},
+			"token": &schema.Schema{
+				Type:          schema.TypeString,
+				Description:   "Token for milking.",
+				Optional:      true,
+				ConflictsWith: []string{"password"},
+			},
+			"port": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Port to listen on.",
+				Optional:    true,
+				Default:     8080,
+			},
+			"tags": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "List of tags.",
+				Optional:    true,
+				MinItems:    1,
+				MaxItems:    5,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"legacy_name": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Legacy name.",
+				Optional:    true,
+				Deprecated:  "Use name instead",
+			},
+			"old_field": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Old field.",
+				Optional:    true,
+				Removed:     "Not supported anymore",
+			},
+			"zone": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Zone where the cow lives.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Generated secret.",
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	r := &Resource{
+		ProviderKey:    "cattle",
+		ProviderName:   "Cattle",
+		ResourceKey:    "cattle_cow",
+		ResourceSlug:   "cattle-cow",
+		ResourceSchema: &resource,
+	}
+	err := r.GenerateResourceMarkdown(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expectedOutput := markdown_flags_output
+	if output != expectedOutput {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
 var markdown_basic_output = `
 ---
 layout: "cattle"
@@ -401,3 +484,56 @@ $ terraform import cattle_cow.example ...
 ` + "```" + `
 
 `
+
+var markdown_flags_output = `
+---
+layout: "cattle"
+page_title: "Cattle: cattle_cow"
+sidebar_current: "docs-cattle-cow"
+description: |-
+  TODO
+---
+
+# cattle_cow
+
+TODO
+
+
+## Example Usage
+
+` + "```" + `
+resource "cattle_cow" "example" {
+  // TODO
+}
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`legacy_name`" + ` - (Optional) Legacy name. **Deprecated**: Use name instead
+* ` + "`name`" + ` - (Required) Name of the cow. Changing this forces a new resource.
+* ` + "`old_field`" + ` - (Optional) Old field. **Removed**: Not supported anymore
+* ` + "`password`" + ` - (Optional, Sensitive) Password for milking. Conflicts with ` + "`token`" + `.
+* ` + "`port`" + ` - (Optional) Port to listen on. Defaults to ` + "`8080`" + `.
+* ` + "`tags`" + ` - (Optional) List of tags. Must have between 1 and 5 items.
+* ` + "`token`" + ` - (Optional) Token for milking. Conflicts with ` + "`password`" + `.
+* ` + "`zone`" + ` - (Optional) Zone where the cow lives.
+
+## Attributes Reference
+
+In addition to the arguments listed above, the following computed attributes are
+exported:
+
+* ` + "`secret`" + ` - (Sensitive) Generated secret.
+* ` + "`zone`" + ` - Zone where the cow lives.
+
+## Import
+
+cattle_cow can be imported using the , e.g.
+
+` + "```" + `
+$ terraform import cattle_cow.example ...
+` + "```" + `
+
+`
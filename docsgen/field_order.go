@@ -0,0 +1,106 @@
+package docsgen
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/internal/keep"
+)
+
+// FieldOrderFunc reports whether field a should be rendered before field b
+type FieldOrderFunc func(a, b *Field) bool
+
+// AlphabeticalOrder sorts fields by name
+func AlphabeticalOrder(a, b *Field) bool {
+	return a.Name < b.Name
+}
+
+// RequiredFirstOrder puts required arguments first, followed by optional arguments
+// and computed-only attributes, sorting fields alphabetically within each group
+func RequiredFirstOrder(a, b *Field) bool {
+	rankA, rankB := requiredFirstRank(a.Schema), requiredFirstRank(b.Schema)
+	if rankA != rankB {
+		return rankA < rankB
+	}
+	return AlphabeticalOrder(a, b)
+}
+
+func requiredFirstRank(s *schema.Schema) int {
+	if s.Required {
+		return 0
+	}
+	if s.Optional {
+		return 1
+	}
+	return 2
+}
+
+// DeclarationOrderFromSource returns order in which fields are declared
+// in the given Go source code (i.e. keys of map[string]*schema.Schema literals).
+// Fields which are declared more than once keep position of the first declaration.
+// Fields not found in the source are put last in alphabetical order.
+func DeclarationOrderFromSource(filename string, src interface{}) (FieldOrderFunc, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := map[string]int{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok || !keep.IsSchemaMapType(cl.Type) {
+			return true
+		}
+		for _, elt := range cl.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.BasicLit)
+			if !ok || key.Kind != token.STRING {
+				continue
+			}
+			name, err := strconv.Unquote(key.Value)
+			if err != nil {
+				continue
+			}
+			if _, ok := positions[name]; !ok {
+				positions[name] = len(positions)
+			}
+		}
+		return true
+	})
+
+	return func(a, b *Field) bool {
+		posA, okA := positions[a.Name]
+		posB, okB := positions[b.Name]
+		if okA && okB {
+			return posA < posB
+		}
+		if okA != okB {
+			return okA
+		}
+		return AlphabeticalOrder(a, b)
+	}, nil
+}
+
+func sortFields(fields map[string]*schema.Schema, less FieldOrderFunc) []*Field {
+	sorted := make([]*Field, 0, len(fields))
+	for name, s := range fields {
+		sorted = append(sorted, &Field{Name: name, Schema: s})
+	}
+	// Start from alphabetical order so that custom orders
+	// which don't distinguish all fields are still deterministic
+	sort.Slice(sorted, func(i, j int) bool {
+		return AlphabeticalOrder(sorted[i], sorted[j])
+	})
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return less(sorted[i], sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,123 @@
+package docsgen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var orderTestFields = map[string]*schema.Schema{
+	"computed_field": {Type: schema.TypeString, Computed: true},
+	"my_optional":    {Type: schema.TypeString, Optional: true},
+	"metadata":       {Type: schema.TypeString, Required: true},
+	"age":            {Type: schema.TypeInt, Optional: true},
+	"name":           {Type: schema.TypeString, Required: true},
+}
+
+func TestSortFields_alphabetical(t *testing.T) {
+	names := fieldNames(sortFields(orderTestFields, AlphabeticalOrder))
+	expectedNames := []string{"age", "computed_field", "metadata", "my_optional", "name"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %q\nGiven: %q", expectedNames, names)
+	}
+}
+
+func TestSortFields_requiredFirst(t *testing.T) {
+	names := fieldNames(sortFields(orderTestFields, RequiredFirstOrder))
+	expectedNames := []string{"metadata", "name", "age", "my_optional", "computed_field"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %q\nGiven: %q", expectedNames, names)
+	}
+}
+
+func TestSortFields_declarationOrder(t *testing.T) {
+	src := `package cattle
+
+import "github.com/hashicorp/terraform/helper/schema"
+
+func resourceCattleCow() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"my_optional": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"metadata": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+`
+	order, err := DeclarationOrderFromSource("resource_cattle_cow.go", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := fieldNames(sortFields(orderTestFields, order))
+	expectedNames := []string{"name", "my_optional", "metadata", "age", "computed_field"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %q\nGiven: %q", expectedNames, names)
+	}
+}
+
+func TestSortFields_custom(t *testing.T) {
+	reverse := func(a, b *Field) bool {
+		return a.Name > b.Name
+	}
+	names := fieldNames(sortFields(orderTestFields, reverse))
+	expectedNames := []string{"name", "my_optional", "metadata", "computed_field", "age"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %q\nGiven: %q", expectedNames, names)
+	}
+}
+
+func fieldNames(fields []*Field) []string {
+	names := make([]string, len(fields), len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func TestResourceDocsFromSchema_nestedOrder(t *testing.T) {
+	r := &Resource{
+		ResourceKey: "cattle_cow",
+		ResourceSchema: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"optional_block": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem:     &schema.Resource{Schema: orderTestFields},
+				},
+				"required_block": {
+					Type:     schema.TypeList,
+					Required: true,
+					Elem:     &schema.Resource{Schema: orderTestFields},
+				},
+			},
+		},
+		FieldOrder: RequiredFirstOrder,
+	}
+	docs := r.resourceDocsFromSchema(r.ResourceSchema)
+
+	blockNames := make([]string, 0)
+	for _, b := range docs.NestedFields {
+		blockNames = append(blockNames, b.Name)
+		names := fieldNames(b.Fields)
+		expectedNames := []string{"metadata", "name", "age", "my_optional", "computed_field"}
+		if !reflect.DeepEqual(names, expectedNames) {
+			t.Fatalf("Expected: %q\nGiven: %q", expectedNames, names)
+		}
+	}
+	expectedBlockNames := []string{"required_block", "optional_block"}
+	if !reflect.DeepEqual(blockNames, expectedBlockNames) {
+		t.Fatalf("Expected: %q\nGiven: %q", expectedBlockNames, blockNames)
+	}
+}
@@ -0,0 +1,204 @@
+package docsgen
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// Profile describes an output target for generated docs,
+// i.e. where the file lives and how the markdown is laid out
+type Profile struct {
+	Name     string
+	PathFunc func(r *Resource) string
+	Template *template.Template
+
+	// GeneratedSections are titles of level-2 sections which are fully generated
+	// and therefore rewritten on regeneration (all other sections are hand-written)
+	GeneratedSections []string
+}
+
+// LegacyWebsiteProfile targets the old terraform.io website
+// (website/docs/r/*.html.markdown with layout & sidebar_current)
+var LegacyWebsiteProfile = &Profile{
+	Name: "legacy-website",
+	PathFunc: func(r *Resource) string {
+		return path.Join("website", "docs", "r", shortResourceName(r)+".html.markdown")
+	},
+	Template: resourceDocsTemplate,
+	GeneratedSections: []string{
+		"Argument Reference",
+		"Nested Blocks",
+		"Attributes Reference",
+	},
+}
+
+// RegistryProfile targets the Terraform Registry
+// (docs/resources/*.md with subcategory & page_title)
+var RegistryProfile = &Profile{
+	Name: "registry",
+	PathFunc: func(r *Resource) string {
+		return path.Join("docs", "resources", shortResourceName(r)+".md")
+	},
+	Template:          registryResourceDocsTemplate,
+	GeneratedSections: []string{"Schema"},
+}
+
+func shortResourceName(r *Resource) string {
+	return strings.TrimPrefix(r.ResourceKey, r.ProviderKey+"_")
+}
+
+var templateFuncs = template.FuncMap{
+	"typeAnnotation":      typeAnnotation,
+	"requiredFields":      requiredFields,
+	"optionalFields":      optionalFields,
+	"readOnlyFields":      readOnlyFields,
+	"isBlock":             isBlock,
+	"isArgument":          isArgument,
+	"isAttribute":         isAttribute,
+	"argumentAnnotation":  argumentAnnotation,
+	"attributeAnnotation": attributeAnnotation,
+	"registryAnnotation":  registryAnnotation,
+	"argumentNotes":       argumentNotes,
+	"attributeNotes":      attributeNotes,
+	"registryNotes":       registryNotes,
+	"fieldGroup":          newTemplateFieldGroup,
+}
+
+type templateFieldGroup struct {
+	Fields   []*Field
+	IsNested bool
+}
+
+func newTemplateFieldGroup(fields []*Field, isNested bool) *templateFieldGroup {
+	return &templateFieldGroup{Fields: fields, IsNested: isNested}
+}
+
+// typeAnnotation returns type of the field in the format used by the Registry
+// e.g. String, List of String, Block List, Max: 1
+func typeAnnotation(s *schema.Schema) string {
+	switch s.Type {
+	case schema.TypeString:
+		return "String"
+	case schema.TypeInt, schema.TypeFloat:
+		return "Number"
+	case schema.TypeBool:
+		return "Boolean"
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		collection := map[schema.ValueType]string{
+			schema.TypeList: "List",
+			schema.TypeSet:  "Set",
+			schema.TypeMap:  "Map",
+		}[s.Type]
+
+		if isBlock(s) {
+			annotation := "Block " + collection
+			if s.MinItems > 0 {
+				annotation += fmt.Sprintf(", Min: %d", s.MinItems)
+			}
+			if s.MaxItems > 0 {
+				annotation += fmt.Sprintf(", Max: %d", s.MaxItems)
+			}
+			return annotation
+		}
+		if elem, ok := s.Elem.(*schema.Schema); ok {
+			return collection + " of " + typeAnnotation(elem)
+		}
+		return collection + " of String"
+	}
+	return "Dynamic"
+}
+
+func isBlock(s *schema.Schema) bool {
+	_, ok := s.Elem.(*schema.Resource)
+	return ok
+}
+
+func requiredFields(fields []*Field) []*Field {
+	return filterFields(fields, func(s *schema.Schema) bool {
+		return s.Required
+	})
+}
+
+func optionalFields(fields []*Field) []*Field {
+	return filterFields(fields, func(s *schema.Schema) bool {
+		return s.Optional
+	})
+}
+
+func readOnlyFields(fields []*Field) []*Field {
+	return filterFields(fields, func(s *schema.Schema) bool {
+		return s.Computed && !s.Optional
+	})
+}
+
+func filterFields(fields []*Field, f func(*schema.Schema) bool) []*Field {
+	filtered := make([]*Field, 0)
+	for _, field := range fields {
+		if f(field.Schema) {
+			filtered = append(filtered, field)
+		}
+	}
+	return filtered
+}
+
+var registryResourceDocsTemplate = template.Must(template.New("registry-resource-docs").Funcs(templateFuncs).Parse(`---
+subcategory: "{{.Subcategory}}"
+page_title: "{{.ProviderName}}: {{.ResourceKey}}"
+description: |-
+  TODO
+---
+
+# {{.ResourceKey}} (Resource)
+
+TODO
+
+## Example Usage
+
+` + "```terraform" + `
+resource "{{.ResourceKey}}" "example" {
+  # TODO
+}
+` + "```" + `
+{{define "registry-field"}}
+- ` + "`{{ .Name }}`" + ` {{registryAnnotation .Schema}} {{ .Schema.Description }}{{registryNotes .Schema}}
+{{- if isBlock .Schema}} (see [below for nested schema](#nestedblock--{{ .Name }})){{end}}
+{{- end}}
+{{- define "registry-fields"}}
+{{- $isNested := .IsNested}}
+{{- with requiredFields .Fields}}
+
+{{if $isNested}}Required:{{else}}### Required{{end}}
+{{range .}}{{template "registry-field" .}}{{end}}
+{{- end}}
+{{- with optionalFields .Fields}}
+
+{{if $isNested}}Optional:{{else}}### Optional{{end}}
+{{range .}}{{template "registry-field" .}}{{end}}
+{{- end}}
+{{- with readOnlyFields .Fields}}
+
+{{if $isNested}}Read-Only:{{else}}### Read-Only{{end}}
+{{range .}}{{template "registry-field" .}}{{end}}
+{{- end}}
+{{- end}}
+## Schema
+{{- template "registry-fields" (fieldGroup .Fields false)}}
+{{- range .NestedFields}}
+
+<a id="nestedblock--{{ .Name }}"></a>
+### Nested Schema for ` + "`{{ .Name }}`" + `
+{{- template "registry-fields" (fieldGroup .Fields true)}}
+{{- end}}
+
+## Import
+
+Import is supported using the following syntax:
+
+` + "```shell" + `
+terraform import {{.ResourceKey}}.example ...
+` + "```" + `
+`))
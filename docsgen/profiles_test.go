@@ -0,0 +1,141 @@
+package docsgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGenerateResourceMarkdown_registry(t *testing.T) {
+	resource := schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"metadata": &schema.Schema{
+				Type:        schema.TypeList,
+				Description: "Standard object's metadata.",
+				Required:    true,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"nested_string": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Description of a nested string",
+						},
+						"nested_ints": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Description: "Description of nested integers",
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
+			},
+			"my_int": &schema.Schema{
+				Type:        schema.TypeInt,
+				Description: "Sample integer.",
+				Required:    true,
+			},
+			"computed_field": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Yada yada yada",
+			},
+			"my_optional_bool": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Standard boolean.",
+				Optional:    true,
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	r := &Resource{
+		ProviderKey:    "cattle",
+		ProviderName:   "Cattle",
+		ResourceKey:    "cattle_cow",
+		ResourceSlug:   "cattle-cow",
+		ResourceSchema: &resource,
+		Subcategory:    "Farm",
+		Profile:        RegistryProfile,
+	}
+	err := r.GenerateResourceMarkdown(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expectedOutput := markdown_registry_output
+	if output != expectedOutput {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestDocsPath(t *testing.T) {
+	r := &Resource{
+		ProviderKey: "cattle",
+		ResourceKey: "cattle_cow",
+	}
+	if path := r.DocsPath(); path != "website/docs/r/cow.html.markdown" {
+		t.Fatalf("Unexpected legacy path: %q", path)
+	}
+
+	r.Profile = RegistryProfile
+	if path := r.DocsPath(); path != "docs/resources/cow.md" {
+		t.Fatalf("Unexpected registry path: %q", path)
+	}
+}
+
+var markdown_registry_output = `---
+subcategory: "Farm"
+page_title: "Cattle: cattle_cow"
+description: |-
+  TODO
+---
+
+# cattle_cow (Resource)
+
+TODO
+
+## Example Usage
+
+` + "```terraform" + `
+resource "cattle_cow" "example" {
+  # TODO
+}
+` + "```" + `
+
+## Schema
+
+### Required
+
+- ` + "`metadata`" + ` (Block List, Max: 1) Standard object's metadata. (see [below for nested schema](#nestedblock--metadata))
+- ` + "`my_int`" + ` (Number) Sample integer.
+
+### Optional
+
+- ` + "`my_optional_bool`" + ` (Boolean) Standard boolean.
+
+### Read-Only
+
+- ` + "`computed_field`" + ` (String) Yada yada yada
+
+<a id="nestedblock--metadata"></a>
+### Nested Schema for ` + "`metadata`" + `
+
+Required:
+
+- ` + "`nested_ints`" + ` (Set of Number) Description of nested integers
+
+Optional:
+
+- ` + "`nested_string`" + ` (String) Description of a nested string
+
+## Import
+
+Import is supported using the following syntax:
+
+` + "```shell" + `
+terraform import cattle_cow.example ...
+` + "```" + `
+`
@@ -0,0 +1,163 @@
+package docsgen
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const (
+	// Regions between these markers are carried over even from generated sections
+	keepBeginMarker = "<!-- terraform-gen:keep -->"
+	keepEndMarker   = "<!-- terraform-gen:end-keep -->"
+)
+
+// RegenerateResourceMarkdown rewrites only generated sections (see Profile.GeneratedSections)
+// of the existing markdown, keeping all hand-written sections (description, example, import etc.)
+// and regions of generated sections wrapped in keep markers intact
+func (r *Resource) RegenerateResourceMarkdown(existing io.Reader, wr io.Writer) error {
+	buf := bytes.NewBuffer([]byte{})
+	err := r.GenerateResourceMarkdown(buf)
+	if err != nil {
+		return err
+	}
+
+	existingContent, err := ioutil.ReadAll(existing)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(existingContent)) == 0 {
+		_, err = wr.Write(buf.Bytes())
+		return err
+	}
+
+	merged := mergeSections(
+		splitMarkdownSections(string(existingContent)),
+		splitMarkdownSections(buf.String()),
+		r.profile().GeneratedSections)
+
+	for _, s := range merged {
+		_, err = io.WriteString(wr, s.Content)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type markdownSection struct {
+	// Heading is empty for content before the first level-2 heading
+	Heading string
+	Content string
+}
+
+// splitMarkdownSections splits markdown by level-2 headings
+// (ignoring those in code blocks), keeping each heading in its section
+func splitMarkdownSections(markdown string) []*markdownSection {
+	sections := []*markdownSection{{}}
+	inCodeBlock := false
+
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n ")
+		if strings.HasPrefix(trimmed, "```") {
+			inCodeBlock = !inCodeBlock
+		}
+		if !inCodeBlock && strings.HasPrefix(trimmed, "## ") {
+			sections = append(sections, &markdownSection{
+				Heading: strings.TrimPrefix(trimmed, "## "),
+			})
+		}
+		last := sections[len(sections)-1]
+		last.Content += line
+	}
+
+	return sections
+}
+
+func mergeSections(existing, generated []*markdownSection, generatedHeadings []string) []*markdownSection {
+	isGenerated := make(map[string]bool, len(generatedHeadings))
+	for _, h := range generatedHeadings {
+		isGenerated[h] = true
+	}
+	generatedByHeading := make(map[string]*markdownSection, len(generated))
+	for _, s := range generated {
+		generatedByHeading[s.Heading] = s
+	}
+
+	merged := make([]*markdownSection, 0)
+	present := make(map[string]bool, 0)
+	for _, s := range existing {
+		if s.Heading == "" || !isGenerated[s.Heading] {
+			merged = append(merged, s)
+			present[s.Heading] = true
+			continue
+		}
+		if gs, ok := generatedByHeading[s.Heading]; ok {
+			merged = append(merged, &markdownSection{
+				Heading: gs.Heading,
+				Content: appendKeptRegions(gs.Content, keptRegions(s.Content)),
+			})
+			present[s.Heading] = true
+		}
+	}
+
+	// Generated sections which didn't exist before go after
+	// the closest preceding section (in order of the generated markdown)
+	for i, gs := range generated {
+		if !isGenerated[gs.Heading] || present[gs.Heading] {
+			continue
+		}
+		position := len(merged)
+		for j := i - 1; j >= 0; j-- {
+			if idx := sectionIndex(merged, generated[j].Heading); idx >= 0 {
+				position = idx + 1
+				break
+			}
+		}
+		merged = append(merged[:position], append([]*markdownSection{gs}, merged[position:]...)...)
+		present[gs.Heading] = true
+	}
+
+	return merged
+}
+
+func sectionIndex(sections []*markdownSection, heading string) int {
+	for i, s := range sections {
+		if s.Heading == heading {
+			return i
+		}
+	}
+	return -1
+}
+
+// keptRegions returns all regions (including markers) wrapped in keep markers
+func keptRegions(content string) []string {
+	regions := make([]string, 0)
+	for {
+		begin := strings.Index(content, keepBeginMarker)
+		if begin < 0 {
+			return regions
+		}
+		end := strings.Index(content[begin:], keepEndMarker)
+		if end < 0 {
+			// Unterminated region keeps the rest of the section
+			return append(regions, strings.TrimRight(content[begin:], "\n"))
+		}
+		end += begin + len(keepEndMarker)
+		regions = append(regions, content[begin:end])
+		content = content[end:]
+	}
+}
+
+func appendKeptRegions(content string, regions []string) string {
+	if len(regions) == 0 {
+		return content
+	}
+	body := strings.TrimRight(content, "\n")
+	trailing := content[len(body):]
+	for _, region := range regions {
+		body += "\n\n" + region
+	}
+	return body + trailing
+}
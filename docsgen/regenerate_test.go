@@ -0,0 +1,202 @@
+package docsgen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestRegenerateResourceMarkdown_preservesManualSections(t *testing.T) {
+	existing := `
+---
+layout: "cattle"
+page_title: "Cattle: cattle_cow"
+sidebar_current: "docs-cattle-cow"
+description: |-
+  Manages a cow.
+---
+
+# cattle\_cow
+
+Manages a cow, moo.
+
+
+## Example Usage
+
+` + "```" + `
+resource "cattle_cow" "example" {
+  name = "daisy"
+}
+
+## Argument Reference (not a heading in code block)
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`old_name`" + ` - (Required) Outdated argument.
+
+<!-- terraform-gen:keep -->
+~> **NOTE:** Cows can't be renamed.
+<!-- terraform-gen:end-keep -->
+
+## Attributes Reference
+
+* ` + "`old_attribute`" + ` - Outdated attribute.
+
+## Timeouts
+
+Hand-written timeouts section.
+
+## Import
+
+Cows can be imported using the name, e.g.
+
+` + "```" + `
+$ terraform import cattle_cow.example daisy
+` + "```" + `
+`
+
+	r := &Resource{
+		ProviderKey:  "cattle",
+		ProviderName: "Cattle",
+		ResourceKey:  "cattle_cow",
+		ResourceSlug: "cattle-cow",
+		ResourceSchema: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Name of the cow.",
+				},
+				"spots": {
+					Type:     schema.TypeList,
+					Optional: true,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"color": {
+								Type:        schema.TypeString,
+								Optional:    true,
+								Description: "Color of the spot.",
+							},
+						},
+					},
+				},
+				"uid": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "Unique ID.",
+				},
+			},
+		},
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := r.RegenerateResourceMarkdown(strings.NewReader(existing), buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := buf.String()
+	expectedOutput := markdown_regenerated_output
+	if output != expectedOutput {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestRegenerateResourceMarkdown_emptyExisting(t *testing.T) {
+	r := &Resource{
+		ProviderKey:    "cattle",
+		ProviderName:   "Cattle",
+		ResourceKey:    "cattle_cow",
+		ResourceSlug:   "cattle-cow",
+		ResourceSchema: checkTestSchema,
+	}
+
+	generated := bytes.NewBuffer([]byte{})
+	err := r.GenerateResourceMarkdown(generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regenerated := bytes.NewBuffer([]byte{})
+	err = r.RegenerateResourceMarkdown(strings.NewReader(""), regenerated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if generated.String() != regenerated.String() {
+		t.Fatalf("Output doesn't match.\nExpected: %s\nGiven: %s\n", generated, regenerated)
+	}
+}
+
+var markdown_regenerated_output = `
+---
+layout: "cattle"
+page_title: "Cattle: cattle_cow"
+sidebar_current: "docs-cattle-cow"
+description: |-
+  Manages a cow.
+---
+
+# cattle\_cow
+
+Manages a cow, moo.
+
+
+## Example Usage
+
+` + "```" + `
+resource "cattle_cow" "example" {
+  name = "daisy"
+}
+
+## Argument Reference (not a heading in code block)
+` + "```" + `
+
+## Argument Reference
+
+The following arguments are supported:
+
+* ` + "`name`" + ` - (Required) Name of the cow.
+* ` + "`spots`" + ` - (Optional) 
+
+<!-- terraform-gen:keep -->
+~> **NOTE:** Cows can't be renamed.
+<!-- terraform-gen:end-keep -->
+
+## Nested Blocks
+
+### ` + "`spots`" + `
+
+#### Arguments
+
+* ` + "`color`" + ` - (Optional) Color of the spot.
+
+#### Attributes
+
+
+
+
+## Attributes Reference
+
+In addition to the arguments listed above, the following computed attributes are
+exported:
+
+* ` + "`uid`" + ` - Unique ID.
+
+## Timeouts
+
+Hand-written timeouts section.
+
+## Import
+
+Cows can be imported using the name, e.g.
+
+` + "```" + `
+$ terraform import cattle_cow.example daisy
+` + "```" + `
+`
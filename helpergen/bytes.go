@@ -0,0 +1,129 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// bytesTypeName returns name of the byte slice type usable in conversions
+func bytesTypeName(t reflect.Type) string {
+	if t.Name() == "" {
+		return "[]byte"
+	}
+	return t.String()
+}
+
+// bytesExpanderFieldValue returns wrapper function & value of the byte slice field,
+// decoding base64 unless the field is raw (terraform-gen:"bytes=raw")
+func (g *generation) bytesExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string) {
+	value := fmt.Sprintf("%s[%q].(string)", g.InputVarName, u.Underscore(sf.Name))
+	if u.BytesEncoding(sf) == u.RawEncoding {
+		return bytesTypeName(sfType), value
+	}
+
+	if g.ReturnErrors {
+		// []byte is assignable to named byte slices, so no conversion is needed
+		g.declare(nil, &FunctionDeclaration{
+			FuncName:     "expandBase64",
+			Arguments:    "v string",
+			Outputs:      "([]byte, error)",
+			FuncBody:     "return base64.StdEncoding.DecodeString(v)",
+			returnsError: true,
+		})
+		return "expandBase64", value
+	}
+	g.declare(nil, &FunctionDeclaration{
+		FuncName:  "expandBase64",
+		Arguments: "v string",
+		Outputs:   "[]byte",
+		FuncBody: `b, err := base64.StdEncoding.DecodeString(v)
+if err != nil {
+log.Printf("ERROR: Unable to decode base64: %s", err)
+}
+return b`,
+	})
+	if sfType.Name() != "" {
+		return sfType.String() + "(expandBase64(%s))", value
+	}
+	return "expandBase64", value
+}
+
+// bytesFlattenerFieldValue returns the flattened value of the byte slice field
+func bytesFlattenerFieldValue(sf *reflect.StructField, value string) string {
+	if u.BytesEncoding(sf) == u.RawEncoding {
+		return fmt.Sprintf("string(%s)", value)
+	}
+	return fmt.Sprintf("base64.StdEncoding.EncodeToString(%s)", value)
+}
+
+// bytesMapExpanderFieldValue returns wrapper function & value of map with byte slice values
+func (g *generation) bytesMapExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string, error) {
+	if sfType.Elem() != reflect.TypeOf([]byte{}) {
+		return "", "", fmt.Errorf("Unable to process: %s %s", sf.Name, sfType.String())
+	}
+
+	funcName, decoded := "expandBase64Map", `b, err := base64.StdEncoding.DecodeString(v.(string))
+if err != nil {
+log.Printf("ERROR: Unable to decode base64 of %s: %s", k, err)
+}
+obj[k] = b`
+	if u.BytesEncoding(sf) == u.RawEncoding {
+		funcName, decoded = "expandBytesMap", "obj[k] = []byte(v.(string))"
+	}
+	outputs, result := "map[string][]byte", "obj"
+	if g.ReturnErrors {
+		// errors are prefixed by keys of the values, e.g. tls.crt: illegal base64 data at input byte 4
+		decoded = `s, ok := v.(string)
+if !ok {
+return nil, fmt.Errorf("%s: expected string, given %T", k, v)
+}
+`
+		if u.BytesEncoding(sf) == u.RawEncoding {
+			decoded += "obj[k] = []byte(s)"
+		} else {
+			decoded += `b, err := base64.StdEncoding.DecodeString(s)
+if err != nil {
+return nil, fmt.Errorf("%s: %s", k, err)
+}
+obj[k] = b`
+		}
+		outputs, result = "(map[string][]byte, error)", "obj, nil"
+	}
+	g.declare(nil, &FunctionDeclaration{
+		FuncName:  funcName,
+		Arguments: "m map[string]interface{}",
+		Outputs:   outputs,
+		FuncBody: fmt.Sprintf(`obj := make(map[string][]byte, len(m))
+for k, v := range m {
+%s
+}
+return %s`, decoded, result),
+		returnsError: g.ReturnErrors,
+	})
+	return funcName, fmt.Sprintf("%s[%q].(map[string]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
+}
+
+// bytesMapFlattenerFieldValue returns the flattened value of map with byte slice values
+func (g *generation) bytesMapFlattenerFieldValue(sf *reflect.StructField, sfType reflect.Type, value string) (string, error) {
+	if sfType.Elem() != reflect.TypeOf([]byte{}) {
+		return "", fmt.Errorf("Unable to process: %s %s", sf.Name, sfType.String())
+	}
+
+	funcName, encoded := "flattenBase64Map", "base64.StdEncoding.EncodeToString(v)"
+	if u.BytesEncoding(sf) == u.RawEncoding {
+		funcName, encoded = "flattenBytesMap", "string(v)"
+	}
+	g.declare(nil, &FunctionDeclaration{
+		FuncName:  funcName,
+		Arguments: "m map[string][]byte",
+		Outputs:   "map[string]interface{}",
+		FuncBody: fmt.Sprintf(`att := make(map[string]interface{}, len(m))
+for k, v := range m {
+att[k] = %s
+}
+return att`, encoded),
+	})
+	return fmt.Sprintf("%s(%s)", funcName, value), nil
+}
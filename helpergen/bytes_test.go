@@ -0,0 +1,204 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+)
+
+type Certificate []byte
+
+type BytesStruct struct {
+	Key         []byte
+	Certificate Certificate
+	Script      []byte `terraform-gen:"bytes=raw"`
+	Data        map[string][]byte
+	StringData  map[string][]byte `terraform-gen:"bytes=raw"`
+}
+
+func TestExpanderFromStruct_bytes(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	output, err := hg.ExpandersFromStruct(BytesStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandBytesStruct": `func expandBytesStruct(l []interface{}) helpergen.BytesStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.BytesStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.BytesStruct{
+Key: expandBase64(cfg["key"].(string)),
+Certificate: helpergen.Certificate(expandBase64(cfg["certificate"].(string))),
+Script: []byte(cfg["script"].(string)),
+Data: expandBase64Map(cfg["data"].(map[string]interface{})),
+StringData: expandBytesMap(cfg["string_data"].(map[string]interface{})),
+}
+return obj
+}`,
+		"expandBase64": `func expandBase64(v string) []byte {
+b, err := base64.StdEncoding.DecodeString(v)
+if err != nil {
+log.Printf("ERROR: Unable to decode base64: %s", err)
+}
+return b
+}`,
+		"expandBase64Map": `func expandBase64Map(m map[string]interface{}) map[string][]byte {
+obj := make(map[string][]byte, len(m))
+for k, v := range m {
+b, err := base64.StdEncoding.DecodeString(v.(string))
+if err != nil {
+log.Printf("ERROR: Unable to decode base64 of %s: %s", k, err)
+}
+obj[k] = b
+}
+return obj
+}`,
+		"expandBytesMap": `func expandBytesMap(m map[string]interface{}) map[string][]byte {
+obj := make(map[string][]byte, len(m))
+for k, v := range m {
+obj[k] = []byte(v.(string))
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_returnErrorsBytes(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		ReturnErrors:  true,
+	}
+	output, err := hg.ExpandersFromStruct(BytesStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"expandBase64": `func expandBase64(v string) ([]byte, error) {
+return base64.StdEncoding.DecodeString(v)
+}`,
+		"expandBase64Map": `func expandBase64Map(m map[string]interface{}) (map[string][]byte, error) {
+obj := make(map[string][]byte, len(m))
+for k, v := range m {
+s, ok := v.(string)
+if !ok {
+return nil, fmt.Errorf("%s: expected string, given %T", k, v)
+}
+b, err := base64.StdEncoding.DecodeString(s)
+if err != nil {
+return nil, fmt.Errorf("%s: %s", k, err)
+}
+obj[k] = b
+}
+return obj, nil
+}`,
+		"expandBytesMap": `func expandBytesMap(m map[string]interface{}) (map[string][]byte, error) {
+obj := make(map[string][]byte, len(m))
+for k, v := range m {
+s, ok := v.(string)
+if !ok {
+return nil, fmt.Errorf("%s: expected string, given %T", k, v)
+}
+obj[k] = []byte(s)
+}
+return obj, nil
+}`,
+		"expandBytesStruct": `func expandBytesStruct(l []interface{}) (helpergen.BytesStruct, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.BytesStruct{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.BytesStruct{
+}
+if v, ok := cfg["key"].(string); ok {
+obj.Key, err = expandBase64(v)
+if err != nil {
+return obj, fmt.Errorf("key: %s", err)
+}
+} else {
+return obj, fmt.Errorf("key: expected string, given %T", cfg["key"])
+}
+if v, ok := cfg["certificate"].(string); ok {
+obj.Certificate, err = expandBase64(v)
+if err != nil {
+return obj, fmt.Errorf("certificate: %s", err)
+}
+} else {
+return obj, fmt.Errorf("certificate: expected string, given %T", cfg["certificate"])
+}
+if v, ok := cfg["script"].(string); ok {
+obj.Script = []byte(v)
+} else {
+return obj, fmt.Errorf("script: expected string, given %T", cfg["script"])
+}
+if v, ok := cfg["data"].(map[string]interface{}); ok {
+obj.Data, err = expandBase64Map(v)
+if err != nil {
+return obj, fmt.Errorf("data.%s", err)
+}
+} else {
+return obj, fmt.Errorf("data: expected map[string]interface{}, given %T", cfg["data"])
+}
+if v, ok := cfg["string_data"].(map[string]interface{}); ok {
+obj.StringData, err = expandBytesMap(v)
+if err != nil {
+return obj, fmt.Errorf("string_data.%s", err)
+}
+} else {
+return obj, fmt.Errorf("string_data: expected map[string]interface{}, given %T", cfg["string_data"])
+}
+return obj, nil
+}`,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, output)
+	}
+}
+
+func TestFlattenerFromStruct_bytes(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	output, err := hg.FlattenersFromStruct(BytesStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenBytesStruct": `func flattenBytesStruct(in helpergen.BytesStruct) []interface{} {
+att := make(map[string]interface{})
+att["key"] = base64.StdEncoding.EncodeToString(in.Key)
+att["certificate"] = base64.StdEncoding.EncodeToString(in.Certificate)
+att["script"] = string(in.Script)
+att["data"] = flattenBase64Map(in.Data)
+att["string_data"] = flattenBytesMap(in.StringData)
+return []interface{}{att}
+}`,
+		"flattenBase64Map": `func flattenBase64Map(m map[string][]byte) map[string]interface{} {
+att := make(map[string]interface{}, len(m))
+for k, v := range m {
+att[k] = base64.StdEncoding.EncodeToString(v)
+}
+return att
+}`,
+		"flattenBytesMap": `func flattenBytesMap(m map[string][]byte) map[string]interface{} {
+att := make(map[string]interface{}, len(m))
+for k, v := range m {
+att[k] = string(v)
+}
+return att
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
@@ -0,0 +1,68 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/radeksimko/terraform-gen/converters"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// lookupConverter returns converter of the type or of the type it points to,
+// the same way schemagen looks it up (e.g. *time.Time is TypeString too)
+func (g *generation) lookupConverter(t reflect.Type) (*converters.Converter, bool) {
+	if c, ok := g.Converters.Lookup(t); ok {
+		return c, true
+	}
+	return g.Converters.Lookup(u.DereferencePtrType(t))
+}
+
+// converterExpanderFieldValue returns wrapper function & value of field with a converter,
+// pointers to converted types are expanded via helper, e.g. expandPtrToTime
+func (g *generation) converterExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string, bool) {
+	c, ok := g.lookupConverter(sfType)
+	if !ok {
+		return "", "", false
+	}
+	g.declareHelpers(c.Helpers, g.ReturnErrors)
+	value := fmt.Sprintf("%s[%q].(%s)", g.InputVarName, u.Underscore(sf.Name), c.SchemaGoType())
+	if _, ok := g.Converters.Lookup(sfType); ok {
+		return c.Expander, value, true
+	}
+
+	t := u.DereferencePtrType(sfType)
+	funcName := "expandPtrTo" + strings.TrimPrefix(g.FuncNameFunc("expand", t), "expand")
+	expanded := wrapValue(c.Expander, "v")
+	decl := &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "v " + c.SchemaGoType(),
+		Outputs:   "*" + t.String(),
+		FuncBody:  fmt.Sprintf("out := %s\nreturn &out", expanded),
+	}
+	if g.isFallible(expanded) {
+		decl.Outputs = "(*" + t.String() + ", error)"
+		decl.FuncBody = fmt.Sprintf(`out, err := %s
+if err != nil {
+return nil, err
+}
+return &out, nil`, expanded)
+		decl.returnsError = true
+	}
+	g.declare(sfType, decl)
+	return funcName, value, true
+}
+
+// converterFlattenerFieldValue returns the flattened value of field with a converter
+func (g *generation) converterFlattenerFieldValue(inputVarName string, sf *reflect.StructField, sfType reflect.Type) (string, bool) {
+	c, ok := g.lookupConverter(sfType)
+	if !ok {
+		return "", false
+	}
+	value := inputVarName + "." + sf.Name
+	if _, ok := g.Converters.Lookup(sfType); !ok {
+		value = "(*" + value + ")"
+	}
+	return fmt.Sprintf(c.Flattener, value), true
+}
@@ -0,0 +1,62 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// expandedObjVarName returns variable holding the struct being expanded
+// (element of the slice for slices)
+func expandedObjVarName(t reflect.Type) string {
+	if t.Kind() == reflect.Slice {
+		return "obj[i]"
+	}
+	return "obj"
+}
+
+// attributeErrorf returns expression creating error of the attribute, path of which
+// is prefixed by index of the element when expanding slices, e.g. 1.port: %s
+func attributeErrorf(parent reflect.Type, format string, args ...string) string {
+	if parent.Kind() == reflect.Slice {
+		format = "%d." + format
+		args = append([]string{"i"}, args...)
+	}
+	return fmt.Sprintf("fmt.Errorf(%s, %s)", strconv.Quote(format), strings.Join(args, ", "))
+}
+
+// fallibleErrorPath returns format of error of the fallible value with path of the attribute,
+// nested blocks & maps report paths relative to the attribute (e.g. ports.1.port or data.tls.crt),
+// others just messages
+func fallibleErrorPath(sf *reflect.StructField, kind reflect.Kind, value string) string {
+	attribute := u.Underscore(sf.Name)
+	if assertedType(value) == "map[string]interface{}" {
+		return attribute + ".%s"
+	}
+	if assertedType(value) != "[]interface{}" {
+		return attribute + ": %s"
+	}
+	if kind == reflect.Struct {
+		// single nested block
+		return attribute + ".0.%s"
+	}
+	return attribute + ".%s"
+}
+
+// wrongTypeErrorf returns expression creating error of attribute of unexpected type
+func wrongTypeErrorf(parent reflect.Type, sf *reflect.StructField, value string) string {
+	format := fmt.Sprintf("%s: expected %s, given %%T", u.Underscore(sf.Name), assertedType(value))
+	return attributeErrorf(parent, format, strings.TrimSuffix(value, ".("+assertedType(value)+")"))
+}
+
+// assertedType returns type of the assertion, e.g. string for in["name"].(string)
+func assertedType(value string) string {
+	i := strings.LastIndex(value, ".(")
+	if i < 0 || !strings.HasSuffix(value, ")") {
+		return ""
+	}
+	return value[i+2 : len(value)-1]
+}
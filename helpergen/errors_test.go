@@ -0,0 +1,250 @@
+package helpergen
+
+import (
+	"testing"
+	"time"
+)
+
+type CheckedContainer struct {
+	Image string
+	Ports []CheckedPort
+}
+
+type CheckedSelector struct {
+	Match string
+}
+
+type CheckedSchedule struct {
+	Start time.Time
+	End   *time.Time
+}
+
+type CheckedPod struct {
+	Name      string
+	Container CheckedContainer
+	Selector  map[string]CheckedSelector
+}
+
+func TestExpanderFromStruct_returnErrors(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		ReturnErrors:  true,
+	}
+	output, err := hg.ExpandersFromStruct(CheckedPod{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"expandCheckedPod": `func expandCheckedPod(l []interface{}) (helpergen.CheckedPod, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.CheckedPod{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.CheckedPod{
+}
+if v, ok := cfg["name"].(string); ok {
+obj.Name = v
+} else {
+return obj, fmt.Errorf("name: expected string, given %T", cfg["name"])
+}
+if v, ok := cfg["container"].([]interface{}); ok {
+obj.Container, err = expandCheckedContainer(v)
+if err != nil {
+return obj, fmt.Errorf("container.0.%s", err)
+}
+} else {
+return obj, fmt.Errorf("container: expected []interface{}, given %T", cfg["container"])
+}
+if v, ok := cfg["selector"].([]interface{}); ok {
+obj.Selector, err = expandMapOfCheckedSelector(v)
+if err != nil {
+return obj, fmt.Errorf("selector.%s", err)
+}
+} else {
+return obj, fmt.Errorf("selector: expected []interface{}, given %T", cfg["selector"])
+}
+return obj, nil
+}`,
+		"expandCheckedContainer": `func expandCheckedContainer(l []interface{}) (helpergen.CheckedContainer, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.CheckedContainer{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.CheckedContainer{
+}
+if v, ok := cfg["image"].(string); ok {
+obj.Image = v
+} else {
+return obj, fmt.Errorf("image: expected string, given %T", cfg["image"])
+}
+if v, ok := cfg["ports"].([]interface{}); ok {
+obj.Ports, err = expandCheckedPort(v)
+if err != nil {
+return obj, fmt.Errorf("ports.%s", err)
+}
+} else {
+return obj, fmt.Errorf("ports: expected []interface{}, given %T", cfg["ports"])
+}
+return obj, nil
+}`,
+		"expandCheckedPort": `func expandCheckedPort(l []interface{}) ([]helpergen.CheckedPort, error) {
+if len(l) == 0 || l[0] == nil {
+return []helpergen.CheckedPort{}, nil
+}
+obj := make([]helpergen.CheckedPort, len(l), len(l))
+for i, n := range l {
+cfg, ok := n.(map[string]interface{})
+if !ok {
+return obj, fmt.Errorf("%d: expected map[string]interface{}, given %T", i, n)
+}
+obj[i] = helpergen.CheckedPort{
+}
+if v, ok := cfg["number"].(int); ok {
+obj[i].Number = uint16(v)
+} else {
+return obj, fmt.Errorf("%d.number: expected int, given %T", i, cfg["number"])
+}
+}
+return obj, nil
+}`,
+		"expandMapOfCheckedSelector": `func expandMapOfCheckedSelector(l []interface{}) (map[string]helpergen.CheckedSelector, error) {
+obj := make(map[string]helpergen.CheckedSelector, len(l))
+for i, n := range l {
+cfg, ok := n.(map[string]interface{})
+if !ok {
+return obj, fmt.Errorf("%d: expected map[string]interface{}, given %T", i, n)
+}
+key, ok := cfg["key"].(string)
+if !ok {
+return obj, fmt.Errorf("%d.key: expected string, given %T", i, cfg["key"])
+}
+v, err := expandCheckedSelector([]interface{}{cfg})
+if err != nil {
+return obj, fmt.Errorf("%d.%s", i, err)
+}
+obj[key] = v
+}
+return obj, nil
+}`,
+	}
+	for name, code := range expected {
+		if output[name] != code {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", code, output[name])
+		}
+	}
+}
+
+func TestExpanderFromStruct_returnErrorsConverters(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		ReturnErrors:  true,
+	}
+	output, err := hg.ExpandersFromStruct(CheckedSchedule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"expandCheckedSchedule": `func expandCheckedSchedule(l []interface{}) (helpergen.CheckedSchedule, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.CheckedSchedule{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.CheckedSchedule{
+}
+if v, ok := cfg["start"].(string); ok {
+obj.Start, err = expandRFC3339Time(v)
+if err != nil {
+return obj, fmt.Errorf("start: %s", err)
+}
+} else {
+return obj, fmt.Errorf("start: expected string, given %T", cfg["start"])
+}
+if v, ok := cfg["end"].(string); ok {
+obj.End, err = expandPtrToTime(v)
+if err != nil {
+return obj, fmt.Errorf("end: %s", err)
+}
+} else {
+return obj, fmt.Errorf("end: expected string, given %T", cfg["end"])
+}
+return obj, nil
+}`,
+		"expandRFC3339Time": `func expandRFC3339Time(v string) (time.Time, error) {
+return time.Parse(time.RFC3339, v)
+}`,
+		"expandPtrToTime": `func expandPtrToTime(v string) (*time.Time, error) {
+out, err := expandRFC3339Time(v)
+if err != nil {
+return nil, err
+}
+return &out, nil
+}`,
+	}
+	for name, code := range expected {
+		if output[name] != code {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", code, output[name])
+		}
+	}
+}
+
+func TestExpanderFromStruct_returnErrorsTypedSlices(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		ReturnErrors:  true,
+	}
+	output, err := hg.ExpandersFromStruct(CheckedEndpoint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"sliceOfPortNumber": `func sliceOfPortNumber(l []interface{}) ([]helpergen.PortNumber, error) {
+out := make([]helpergen.PortNumber, len(l))
+for i, v := range l {
+n, ok := v.(int)
+if !ok {
+return nil, fmt.Errorf("%d: expected int, given %T", i, v)
+}
+out[i] = helpergen.PortNumber(n)
+}
+return out, nil
+}`,
+		"sliceOfPtrUint64": `func sliceOfPtrUint64(l []interface{}) ([]*uint64, error) {
+out := make([]*uint64, len(l))
+for i, v := range l {
+n, ok := v.(int)
+if !ok {
+return nil, fmt.Errorf("%d: expected int, given %T", i, v)
+}
+e := uint64(n)
+out[i] = &e
+}
+return out, nil
+}`,
+	}
+	for name, code := range expected {
+		if output[name] != code {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", code, output[name])
+		}
+	}
+}
+
+func TestAssertedType(t *testing.T) {
+	for value, expected := range map[string]string{
+		`in["name"].(string)`:         "string",
+		`in["ports"].([]interface{})`: "[]interface{}",
+		`expandPorts(in["ports"])`:    "",
+	} {
+		if given := assertedType(value); given != expected {
+			t.Fatalf("Expected %q for %s, given: %q", expected, value, given)
+		}
+	}
+}
@@ -4,63 +4,102 @@ import (
 	"fmt"
 	"log"
 	"reflect"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	u "github.com/radeksimko/terraform-gen/internal/util"
 )
 
-func (hg *HelperGenerator) ExpandersFromStruct(iface interface{}) map[string]string {
-	hg.init()
-	hg.generateExpandersFromStruct(iface)
-	return hg.renderDeclarations()
+// ExpandersFromStruct generates expanders of the struct & all nested structs by name,
+// it fails if two different types would share the same function name
+func (hg *HelperGenerator) ExpandersFromStruct(iface interface{}) (map[string]string, error) {
+	g := hg.newGeneration()
+	g.generateExpanders(iface)
+	return g.renderDeclarations()
 }
 
-func (hg *HelperGenerator) generateExpandersFromStruct(iface interface{}) string {
+func (g *generation) generateExpanders(iface interface{}) {
+	if g.Backend.Framework {
+		g.generateFrameworkExpander(reflect.TypeOf(iface))
+	} else {
+		g.collectShapes(reflect.TypeOf(iface))
+		g.generateExpandersFromStruct(iface)
+	}
+}
+
+func (g *generation) generateExpandersFromStruct(iface interface{}) string {
 	t := reflect.TypeOf(iface)
 	rawType := getRawType(t)
 
-	funcName := expanderFuncNameFromType(t)
-	funcBody := hg.expanderBodyBeginning(t)
+	funcName := g.funcName("expand", t)
+
+	// slice expanders switch to cfg (see expanderBodyBeginning) just for themselves
+	mapVarName := g.mapVarName
+	defer func() {
+		g.mapVarName = mapVarName
+	}()
 
-	// Inline fields (typically those we never expect to be empty)
-	funcBody += hg.inlineExpanderDeclarationBeginning(t)
+	// Inline fields (typically those we never expect to be empty),
+	// checked ones are assigned after the declaration, so errors can be returned
+	inline, checked := "", ""
+	usesErr := false
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
-		body, err := hg.inlineExpanderField(sf.Name, sf.Type, iface, &sf)
+		body, fallible, err := g.inlineExpanderField(sf.Name, sf.Type, iface, &sf, t)
 		if err != nil {
 			log.Printf("Skipping %s (inline): %s", sf.Name, err)
 			continue
 		}
-		funcBody += body
+		usesErr = usesErr || fallible
+		if fallible || g.ReturnErrors {
+			checked += body
+		} else {
+			inline += body
+		}
 	}
-	funcBody += hg.inlineExpanderDeclarationEnd(t)
 
 	// Outline fields (typically optional)
+	outline := ""
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
-		body, err := hg.outlineExpanderField(sf.Name, sf.Type, iface, &sf)
+		body, fallible, err := g.outlineExpanderField(sf.Name, sf.Type, iface, &sf, t)
 		if err != nil {
 			log.Printf("Skipping %s (outline): %s", sf.Name, err)
 			continue
 		}
-		funcBody += body
+		usesErr = usesErr || fallible
+		outline += body
+	}
+	returnsError := usesErr || g.ReturnErrors
+
+	funcBody := g.expanderBodyBeginning(t, returnsError)
+	if usesErr {
+		funcBody += "var err error\n"
 	}
+	funcBody += g.inlineExpanderDeclarationBeginning(t)
+	funcBody += inline
+	funcBody += g.inlineExpanderDeclarationEnd(t)
+	funcBody += checked
+	funcBody += outline
+	funcBody += g.expanderBodyEnd(t, returnsError)
 
-	funcBody += hg.expanderBodyEnd(t)
 	args := "l" + " []interface{}"
-	hg.declarations[funcName] = &FunctionDeclaration{
-		PkgPath:   t.PkgPath(),
-		FuncName:  funcName,
-		Arguments: args,
-		Outputs:   interfaceFromType(t),
-		FuncBody:  funcBody,
+	outputs := interfaceFromType(t)
+	if returnsError {
+		outputs = "(" + outputs + ", error)"
 	}
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:      t.PkgPath(),
+		FuncName:     funcName,
+		Arguments:    args,
+		Outputs:      outputs,
+		FuncBody:     funcBody,
+		returnsError: returnsError,
+	})
 
 	return funcName
 }
 
-func (hg *HelperGenerator) inlineExpanderDeclarationBeginning(t reflect.Type) string {
+func (g *generation) inlineExpanderDeclarationBeginning(t reflect.Type) string {
 	if t.Kind() == reflect.Slice {
 		ptr := ""
 		t := t.Elem()
@@ -80,57 +119,75 @@ func (hg *HelperGenerator) inlineExpanderDeclarationBeginning(t reflect.Type) st
 	return "obj := " + ptr + t.String() + "{\n"
 }
 
-func (hg *HelperGenerator) inlineExpanderDeclarationEnd(t reflect.Type) string {
+func (g *generation) inlineExpanderDeclarationEnd(t reflect.Type) string {
 	return "}\n"
 }
 
-func (hg *HelperGenerator) inlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
+// inlineExpanderField returns field of the declaration, or assignment if the value is checked
+// (see ReturnErrors) or fallible (i.e. comes from function returning error, which is reported as true)
+func (g *generation) inlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, parent reflect.Type) (string, bool, error) {
 	rawType := u.DereferencePtrType(sfType)
 	kind := rawType.Kind()
 	s := &schema.Schema{}
 
 	if sf != nil {
 		var ok bool
-		kind, ok = hg.InlineFieldFilterFunc(iface, sf, kind, s)
+		kind, ok = g.InlineFieldFilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (inline filter)", sf.Name)
+			return "", false, fmt.Errorf("Skipping %q (inline filter)", sf.Name)
 		}
 	}
 
-	wrapperFunc, value, err := hg.expanderFieldValue(kind, sf, sfName, sfType)
+	wrapperFunc, value, err := g.expanderFieldValue(kind, sf, sfName, sfType)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	leftSide := sf.Name
+	objVarName := expandedObjVarName(parent)
 
+	if g.ReturnErrors {
+		assignment, fallible := g.expanderAssignment(parent, sf, kind, wrapperFunc, value)
+		return fmt.Sprintf(`if v, ok := %s; ok {
+%s} else {
+return obj, %s
+}
+`, value, assignment, wrongTypeErrorf(parent, sf, value)), fallible, nil
+	}
+
+	assignedValue := value
 	if wrapperFunc != "" {
-		value = fmt.Sprintf("%s(%s)", wrapperFunc, value)
+		assignedValue = wrapValue(wrapperFunc, value)
 	}
 
-	return fmt.Sprintf("%s: %s,\n", leftSide, value), nil
+	if g.isFallible(assignedValue) {
+		return fallibleAssignment(objVarName+"."+leftSide, assignedValue, "obj",
+			attributeErrorf(parent, fallibleErrorPath(sf, kind, value), "err")), true, nil
+	}
+	return fmt.Sprintf("%s: %s,\n", leftSide, assignedValue), false, nil
 }
 
-func (hg *HelperGenerator) outlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (string, error) {
+func (g *generation) outlineExpanderField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, parent reflect.Type) (string, bool, error) {
 	rawType := u.DereferencePtrType(sfType)
 	kind := rawType.Kind()
 	s := &schema.Schema{}
 
 	if sf != nil {
 		var ok bool
-		kind, ok = hg.OutlineFieldFilterFunc(iface, sf, kind, s)
+		kind, ok = g.OutlineFieldFilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (outline filter)", sf.Name)
+			return "", false, fmt.Errorf("Skipping %q (outline filter)", sf.Name)
 		}
 	}
 
-	wrapperFunc, value, err := hg.expanderFieldValue(kind, sf, sfName, sfType)
+	wrapperFunc, value, err := g.expanderFieldValue(kind, sf, sfName, sfType)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 	leftSide := sf.Name
+	objVarName := expandedObjVarName(parent)
 	assignedValue := "v"
 	if wrapperFunc != "" {
-		assignedValue = fmt.Sprintf("%s(v)", wrapperFunc)
+		assignedValue = wrapValue(wrapperFunc, "v")
 	}
 
 	lengthCondition := ""
@@ -138,73 +195,138 @@ func (hg *HelperGenerator) outlineExpanderField(sfName string, sfType reflect.Ty
 	case reflect.Struct, reflect.Slice, reflect.Map:
 		lengthCondition = " && len(v) > 0"
 	}
+	if u.IsJSONType(sfType) {
+		// Empty strings are not JSON
+		lengthCondition = " && len(v) > 0"
+	}
+	if c, ok := g.lookupConverter(sfType); ok {
+		lengthCondition = ""
+		if c.SchemaType == schema.TypeString {
+			// Empty strings cannot be converted
+			lengthCondition = " && len(v) > 0"
+		}
+	}
+
+	if g.isFallible(assignedValue) {
+		assignment, _ := g.expanderAssignment(parent, sf, kind, wrapperFunc, value)
+		return fmt.Sprintf("if v, ok := %s; ok%s {\n%s}\n", value, lengthCondition, assignment), true, nil
+	}
 
 	return fmt.Sprintf(`if v, ok := %s; ok%s {
 %s.%s = %s
 }
-`, value, lengthCondition, "obj", leftSide, assignedValue), nil
+`, value, lengthCondition, objVarName, leftSide, assignedValue), false, nil
 }
 
-func (hg *HelperGenerator) expanderFieldValue(kind reflect.Kind, sf *reflect.StructField, sfName string, sfType reflect.Type) (string, string, error) {
+// expanderAssignment assigns v (asserted value) to the field & reports whether it's fallible,
+// errors of fallible values are returned with path of the attribute
+func (g *generation) expanderAssignment(parent reflect.Type, sf *reflect.StructField, kind reflect.Kind, wrapperFunc, value string) (string, bool) {
+	leftSide := expandedObjVarName(parent) + "." + sf.Name
+	assignedValue := "v"
+	if wrapperFunc != "" {
+		assignedValue = wrapValue(wrapperFunc, "v")
+	}
+	if g.isFallible(assignedValue) {
+		return fallibleAssignment(leftSide, assignedValue, "obj",
+			attributeErrorf(parent, fallibleErrorPath(sf, kind, value), "err")), true
+	}
+	return fmt.Sprintf("%s = %s\n", leftSide, assignedValue), false
+}
+
+func (g *generation) expanderFieldValue(kind reflect.Kind, sf *reflect.StructField, sfName string, sfType reflect.Type) (string, string, error) {
+	if wrapperFunc, value, ok := g.converterExpanderFieldValue(sf, sfType); ok {
+		return wrapperFunc, value, nil
+	}
+	if wrapperFunc, value, ok := g.protoExpanderFieldValue(sf, sfType); ok {
+		return wrapperFunc, value, nil
+	}
+	if u.IsJSONType(sfType) {
+		wrapperFunc, value := g.jsonExpanderFieldValue(sf, sfType)
+		return wrapperFunc, value, nil
+	}
+
 	switch kind {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
-		castType := sfType.String()
-
-		if sfType.Kind() == reflect.Ptr {
-			castType = sfType.Elem().String()
-			firstLetter := strings.ToUpper(string(castType[0]))
-			ptrHelperFunc := "ptrTo" + firstLetter + castType[1:]
-			return ptrHelperFunc, fmt.Sprintf("%s[%q].(%v)", hg.InputVarName, u.Underscore(sf.Name), castType), nil
-		}
-
-		return "", fmt.Sprintf("%s[%q].(%v)", hg.InputVarName, u.Underscore(sf.Name), castType), nil
+		wrapperFunc, value := g.primitiveExpanderFieldValue(sf, sfType)
+		return wrapperFunc, value, nil
 	case reflect.Map:
+		if u.DereferencePtrType(sfType).Key().Kind() != reflect.String {
+			return "", "", fmt.Errorf("Unable to process: %s %s (keys are not strings)", sfName, sfType.String())
+		}
+		if isComplexMap(sfType) {
+			return g.mapExpanderFieldValue(sf, sfType)
+		}
+		if u.IsByteSlice(sfType.Elem()) {
+			return g.bytesMapExpanderFieldValue(sf, sfType)
+		}
 		// TODO: map[string]*string
 		// TODO: map[string]int
 		// TODO: map[string]bool
 		// TODO: map[string]float
-		return "expandStringMap", fmt.Sprintf("%s[%q].(map[string]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+		return "expandStringMap", fmt.Sprintf("%s[%q].(map[string]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
 	case reflect.Slice:
+		if u.IsByteSlice(sfType) {
+			wrapperFunc, value := g.bytesExpanderFieldValue(sf, sfType)
+			return wrapperFunc, value, nil
+		}
 		// TODO: s.Type == TypeSet
 		sliceOf := sfType.Elem()
 		switch sliceOf.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 			reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
 			// Slice of primitive data types
-			funcName := hg.primitiveSliceExpanderForType(sliceOf, sfType)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			funcName := g.primitiveSliceExpanderForType(sliceOf, sfType)
+			return funcName, fmt.Sprintf("%s[%q].([]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
 		case reflect.Ptr:
 			ptrTo := sliceOf.Elem()
-			funcName := hg.primitiveSliceExpanderForType(ptrTo, sfType)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			funcName := g.primitiveSliceExpanderForType(ptrTo, sfType)
+			return funcName, fmt.Sprintf("%s[%q].([]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
 		case reflect.Struct:
 			iface := reflect.New(sfType).Elem().Interface()
-			funcName := hg.generateExpandersFromStruct(iface)
-			return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+			funcName := g.generateExpandersFromStruct(iface)
+			return funcName, fmt.Sprintf("%s[%q].([]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
 		}
 	case reflect.Struct:
 		iface := reflect.New(sfType).Elem().Interface()
-		funcName := hg.generateExpandersFromStruct(iface)
-		return funcName, fmt.Sprintf("%s[%q].([]interface{})", hg.InputVarName, u.Underscore(sf.Name)), nil
+		funcName := g.generateExpandersFromStruct(iface)
+		return funcName, fmt.Sprintf("%s[%q].([]interface{})", g.InputVarName, u.Underscore(sf.Name)), nil
 	}
 
 	f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
 	return "", "", fmt.Errorf("Unable to process: %s", f)
 }
 
-func (hg *HelperGenerator) expanderBodyBeginning(t reflect.Type) string {
+// elementAssertion asserts element n of the list (or of the map represented by it) to be cfg,
+// failing with index of the element (e.g. 1: expected ...) instead of panicking if errors are returned
+func elementAssertion(returnsError bool) string {
+	if !returnsError {
+		return "cfg := n.(map[string]interface{})\n"
+	}
+	return `cfg, ok := n.(map[string]interface{})
+if !ok {
+return obj, fmt.Errorf("%d: expected map[string]interface{}, given %T", i, n)
+}
+`
+}
+
+func (g *generation) expanderBodyBeginning(t reflect.Type, returnsError bool) string {
+	nilErr := ""
+	if returnsError {
+		nilErr = ", nil"
+	}
+
 	code := ""
 	if t.Kind() == reflect.Slice {
 		code += `if len(l) == 0 || l[0] == nil {
-return ` + t.String() + `{}
+return ` + t.String() + `{}` + nilErr + `
 }
 obj := make(` + t.String() + `, len(l), len(l))
 for i, n := range l {
-cfg := n.(map[string]interface{})
-`
-		hg.mapVarName = "cfg"
+` + elementAssertion(returnsError)
+		g.mapVarName = "cfg"
 		return code
 	}
 
@@ -215,30 +337,43 @@ cfg := n.(map[string]interface{})
 	}
 
 	code += `if len(l) == 0 || l[0] == nil {
-return ` + ptr + t.String() + `{}
+return ` + ptr + t.String() + `{}` + nilErr + `
 }
-` + hg.InputVarName + " := l[0].(map[string]interface{})\n"
+` + g.InputVarName + " := l[0].(map[string]interface{})\n"
 
 	return code
 }
 
-func (hg *HelperGenerator) expanderBodyEnd(t reflect.Type) string {
+func (g *generation) expanderBodyEnd(t reflect.Type, returnsError bool) string {
 	code := ""
 	if t.Kind() == reflect.Slice {
 		code += "}\n"
 	}
+	if returnsError {
+		return code + "return obj, nil"
+	}
 	return code + "return obj"
 }
 
-func (hg *HelperGenerator) primitiveSliceExpanderForType(t reflect.Type, sfType reflect.Type) string {
+func (g *generation) primitiveSliceExpanderForType(t reflect.Type, sfType reflect.Type) string {
 	sliceOf := sfType.Elem()
 	switch t.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		if !isSchemaGoType(t) {
+			// sized & named types, e.g. []int32 or []v1.Capability
+			return g.typedSliceExpander(t, sliceOf.Kind() == reflect.Ptr)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int:
 		if sliceOf.Kind() == reflect.Ptr {
 			return "sliceOfPtrInt"
 		}
 		return "sliceOfInt"
-	case reflect.Float32, reflect.Float64:
+	case reflect.Float64:
 		if sliceOf.Kind() == reflect.Ptr {
 			return "sliceOfPtrFloat"
 		}
@@ -255,14 +390,7 @@ func (hg *HelperGenerator) primitiveSliceExpanderForType(t reflect.Type, sfType
 		return "sliceOfBool"
 	case reflect.Struct:
 		iface := reflect.New(sfType).Elem().Interface()
-		return hg.generateExpandersFromStruct(iface)
+		return g.generateExpandersFromStruct(iface)
 	}
 	return ""
 }
-
-func expanderFuncNameFromType(t reflect.Type) string {
-	// pkg.TypeName
-	parts := strings.Split(t.String(), ".")
-	rawTypeName := parts[1]
-	return "expand" + rawTypeName
-}
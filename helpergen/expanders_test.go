@@ -3,8 +3,10 @@ package helpergen
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/converters"
 )
 
 func TestExpanderFromStruct_primitives(t *testing.T) {
@@ -27,7 +29,10 @@ func TestExpanderFromStruct_primitives(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -36,14 +41,14 @@ return helpergen.SimpleStruct{}
 cfg := l[0].(map[string]interface{})
 obj := helpergen.SimpleStruct{
 MyInt: cfg["my_int"].(int),
-MyInt8: cfg["my_int8"].(int8),
-MyInt16: cfg["my_int16"].(int16),
-MyInt32: cfg["my_int32"].(int32),
-MyInt64: cfg["my_int64"].(int64),
-MyUInt: cfg["my_u_int"].(uint),
-MyUInt32: cfg["my_u_int32"].(uint32),
-MyUInt64: cfg["my_u_int64"].(uint64),
-MyFloat32: cfg["my_float32"].(float32),
+MyInt8: int8(cfg["my_int8"].(int)),
+MyInt16: int16(cfg["my_int16"].(int)),
+MyInt32: int32(cfg["my_int32"].(int)),
+MyInt64: int64(cfg["my_int64"].(int)),
+MyUInt: uint(cfg["my_u_int"].(int)),
+MyUInt32: uint32(cfg["my_u_int32"].(int)),
+MyUInt64: uint64(cfg["my_u_int64"].(int)),
+MyFloat32: float32(cfg["my_float32"].(float64)),
 MyFloat64: cfg["my_float64"].(float64),
 MyString: cfg["my_string"].(string),
 MyBool: cfg["my_bool"].(bool),
@@ -73,7 +78,10 @@ func TestExpanderFromPtrToStruct_primitives(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(&SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(&SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) *helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -82,11 +90,11 @@ return &helpergen.SimpleStruct{}
 cfg := l[0].(map[string]interface{})
 obj := &helpergen.SimpleStruct{
 MyInt: cfg["my_int"].(int),
-MyInt8: cfg["my_int8"].(int8),
-MyInt16: cfg["my_int16"].(int16),
-MyInt32: cfg["my_int32"].(int32),
-MyInt64: cfg["my_int64"].(int64),
-MyFloat32: cfg["my_float32"].(float32),
+MyInt8: int8(cfg["my_int8"].(int)),
+MyInt16: int16(cfg["my_int16"].(int)),
+MyInt32: int32(cfg["my_int32"].(int)),
+MyInt64: int64(cfg["my_int64"].(int)),
+MyFloat32: float32(cfg["my_float32"].(float64)),
 MyFloat64: cfg["my_float64"].(float64),
 MyString: cfg["my_string"].(string),
 MyBool: cfg["my_bool"].(bool),
@@ -119,7 +127,10 @@ func TestExpanderFromPtrToStruct_ptrsToPrimitives(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(&SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(&SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) *helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -128,14 +139,14 @@ return &helpergen.SimpleStruct{}
 cfg := l[0].(map[string]interface{})
 obj := &helpergen.SimpleStruct{
 MyInt: ptrToInt(cfg["my_int"].(int)),
-MyInt8: ptrToInt8(cfg["my_int8"].(int8)),
-MyInt16: ptrToInt16(cfg["my_int16"].(int16)),
-MyInt32: ptrToInt32(cfg["my_int32"].(int32)),
-MyInt64: ptrToInt64(cfg["my_int64"].(int64)),
-MyUInt: ptrToUint(cfg["my_u_int"].(uint)),
-MyUInt32: ptrToUint32(cfg["my_u_int32"].(uint32)),
-MyUInt64: ptrToUint64(cfg["my_u_int64"].(uint64)),
-MyFloat32: ptrToFloat32(cfg["my_float32"].(float32)),
+MyInt8: ptrToInt8(int8(cfg["my_int8"].(int))),
+MyInt16: ptrToInt16(int16(cfg["my_int16"].(int))),
+MyInt32: ptrToInt32(int32(cfg["my_int32"].(int))),
+MyInt64: ptrToInt64(int64(cfg["my_int64"].(int))),
+MyUInt: ptrToUint(uint(cfg["my_u_int"].(int))),
+MyUInt32: ptrToUint32(uint32(cfg["my_u_int32"].(int))),
+MyUInt64: ptrToUint64(uint64(cfg["my_u_int64"].(int))),
+MyFloat32: ptrToFloat32(float32(cfg["my_float32"].(float64))),
 MyFloat64: ptrToFloat64(cfg["my_float64"].(float64)),
 MyString: ptrToString(cfg["my_string"].(string)),
 MyBool: ptrToBool(cfg["my_bool"].(bool)),
@@ -159,7 +170,10 @@ func TestExpanderFromStruct_stringMap(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -195,7 +209,10 @@ func TestExpanderFromStruct_primitiveSlices(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -204,8 +221,8 @@ return helpergen.SimpleStruct{}
 cfg := l[0].(map[string]interface{})
 obj := helpergen.SimpleStruct{
 SliceOfInt: sliceOfInt(cfg["slice_of_int"].([]interface{})),
-SliceOfInt32: sliceOfInt(cfg["slice_of_int32"].([]interface{})),
-SliceOfInt64: sliceOfInt(cfg["slice_of_int64"].([]interface{})),
+SliceOfInt32: sliceOfInt32(cfg["slice_of_int32"].([]interface{})),
+SliceOfInt64: sliceOfInt64(cfg["slice_of_int64"].([]interface{})),
 SliceOfString: sliceOfString(cfg["slice_of_string"].([]interface{})),
 SliceOfFloat64: sliceOfFloat(cfg["slice_of_float64"].([]interface{})),
 SliceOfBool: sliceOfBool(cfg["slice_of_bool"].([]interface{})),
@@ -213,6 +230,20 @@ SimpleInt: cfg["simple_int"].(int),
 SimpleString: cfg["simple_string"].(string),
 }
 return obj
+}`,
+		"sliceOfInt32": `func sliceOfInt32(l []interface{}) []int32 {
+out := make([]int32, len(l))
+for i, v := range l {
+out[i] = int32(v.(int))
+}
+return out
+}`,
+		"sliceOfInt64": `func sliceOfInt64(l []interface{}) []int64 {
+out := make([]int64, len(l))
+for i, v := range l {
+out[i] = int64(v.(int))
+}
+return out
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -252,7 +283,10 @@ func TestExpanderFromStruct_optionalPrimitiveSlices(t *testing.T) {
 		return k, false
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -267,10 +301,10 @@ if v, ok := cfg["slice_of_int"].([]interface{}); ok && len(v) > 0 {
 obj.SliceOfInt = sliceOfInt(v)
 }
 if v, ok := cfg["slice_of_int32"].([]interface{}); ok && len(v) > 0 {
-obj.SliceOfInt32 = sliceOfInt(v)
+obj.SliceOfInt32 = sliceOfInt32(v)
 }
 if v, ok := cfg["slice_of_int64"].([]interface{}); ok && len(v) > 0 {
-obj.SliceOfInt64 = sliceOfInt(v)
+obj.SliceOfInt64 = sliceOfInt64(v)
 }
 if v, ok := cfg["slice_of_string"].([]interface{}); ok && len(v) > 0 {
 obj.SliceOfString = sliceOfString(v)
@@ -282,6 +316,20 @@ if v, ok := cfg["slice_of_bool"].([]interface{}); ok && len(v) > 0 {
 obj.SliceOfBool = sliceOfBool(v)
 }
 return obj
+}`,
+		"sliceOfInt32": `func sliceOfInt32(l []interface{}) []int32 {
+out := make([]int32, len(l))
+for i, v := range l {
+out[i] = int32(v.(int))
+}
+return out
+}`,
+		"sliceOfInt64": `func sliceOfInt64(l []interface{}) []int64 {
+out := make([]int64, len(l))
+for i, v := range l {
+out[i] = int64(v.(int))
+}
+return out
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -305,7 +353,10 @@ func TestExpanderFromStruct_primitivePtrSlices(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -314,8 +365,8 @@ return helpergen.SimpleStruct{}
 cfg := l[0].(map[string]interface{})
 obj := helpergen.SimpleStruct{
 SliceOfInt: sliceOfPtrInt(cfg["slice_of_int"].([]interface{})),
-SliceOfInt32: sliceOfPtrInt(cfg["slice_of_int32"].([]interface{})),
-SliceOfInt64: sliceOfPtrInt(cfg["slice_of_int64"].([]interface{})),
+SliceOfInt32: sliceOfPtrInt32(cfg["slice_of_int32"].([]interface{})),
+SliceOfInt64: sliceOfPtrInt64(cfg["slice_of_int64"].([]interface{})),
 SliceOfString: sliceOfPtrString(cfg["slice_of_string"].([]interface{})),
 SliceOfFloat64: sliceOfPtrFloat(cfg["slice_of_float64"].([]interface{})),
 SliceOfBool: sliceOfPtrBool(cfg["slice_of_bool"].([]interface{})),
@@ -323,6 +374,22 @@ SimpleInt: cfg["simple_int"].(int),
 SimpleString: cfg["simple_string"].(string),
 }
 return obj
+}`,
+		"sliceOfPtrInt32": `func sliceOfPtrInt32(l []interface{}) []*int32 {
+out := make([]*int32, len(l))
+for i, v := range l {
+e := int32(v.(int))
+out[i] = &e
+}
+return out
+}`,
+		"sliceOfPtrInt64": `func sliceOfPtrInt64(l []interface{}) []*int64 {
+out := make([]*int64, len(l))
+for i, v := range l {
+e := int64(v.(int))
+out[i] = &e
+}
+return out
 }`,
 	}
 	if !reflect.DeepEqual(output, expectedOutput) {
@@ -345,7 +412,10 @@ func TestExpanderFromStruct_structSlice(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -394,7 +464,10 @@ func TestExpanderFromStruct_structPtrSlice(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -444,7 +517,10 @@ func TestExpanderFromStruct_nestedStruct(t *testing.T) {
 		OutputVarName: "obj",
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -504,7 +580,10 @@ func TestExpanderFromStruct_optionalPrimitives(t *testing.T) {
 		return k, false
 	}
 
-	output := hg.ExpandersFromStruct(SimpleStruct{})
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"expandSimpleStruct": `func expandSimpleStruct(l []interface{}) helpergen.SimpleStruct {
 if len(l) == 0 || l[0] == nil {
@@ -528,3 +607,102 @@ return obj
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
 	}
 }
+
+type Quantity struct {
+	Value string
+}
+
+type ConvertedStruct struct {
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	Timeout   time.Duration
+	Memory    Quantity
+}
+
+func quantityRegistry() *converters.Registry {
+	r := converters.NewBuiltinRegistry()
+	r.Register(Quantity{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "resource.MustParse(%s)",
+		Flattener:  "%s.String()",
+	})
+	return r
+}
+
+func TestExpanderFromStruct_converters(t *testing.T) {
+	hg := &HelperGenerator{
+		InlineFieldFilterFunc:  rejectAllFilter,
+		OutlineFieldFilterFunc: acceptAllFilter,
+		InputVarName:           "in",
+		OutputVarName:          "obj",
+		Converters:             quantityRegistry(),
+	}
+
+	output, err := hg.ExpandersFromStruct(&ConvertedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandConvertedStruct": `func expandConvertedStruct(l []interface{}) *helpergen.ConvertedStruct {
+if len(l) == 0 || l[0] == nil {
+return &helpergen.ConvertedStruct{}
+}
+in := l[0].(map[string]interface{})
+obj := &helpergen.ConvertedStruct{
+}
+if v, ok := in["created_at"].(string); ok && len(v) > 0 {
+obj.CreatedAt = expandRFC3339Time(v)
+}
+if v, ok := in["expires_at"].(string); ok && len(v) > 0 {
+obj.ExpiresAt = expandPtrToTime(v)
+}
+if v, ok := in["timeout"].(string); ok && len(v) > 0 {
+obj.Timeout = expandTimeDuration(v)
+}
+if v, ok := in["memory"].(string); ok && len(v) > 0 {
+obj.Memory = resource.MustParse(v)
+}
+return obj
+}`,
+		"expandRFC3339Time": `func expandRFC3339Time(v string) time.Time {
+t, err := time.Parse(time.RFC3339, v)
+if err != nil {
+log.Printf("ERROR: Unable to parse time: %s", err)
+}
+return t
+}`,
+		"expandPtrToTime": `func expandPtrToTime(v string) *time.Time {
+out := expandRFC3339Time(v)
+return &out
+}`,
+		"expandTimeDuration": `func expandTimeDuration(v string) time.Duration {
+d, err := time.ParseDuration(v)
+if err != nil {
+log.Printf("ERROR: Unable to parse duration: %s", err)
+}
+return d
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestExpanderFromStruct_restoresMapVarName(t *testing.T) {
+	type NestedStruct struct {
+		NestedInt int
+	}
+	type SimpleStruct struct {
+		NestedSlice []NestedStruct
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+	}
+
+	g := hg.newGeneration()
+	g.generateExpanders(SimpleStruct{})
+	if g.mapVarName != "obj" {
+		t.Fatalf("Expected mapVarName to be restored to %q, given: %q", "obj", g.mapVarName)
+	}
+}
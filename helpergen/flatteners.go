@@ -4,156 +4,215 @@ import (
 	"fmt"
 	"log"
 	"reflect"
-	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	u "github.com/radeksimko/terraform-gen/internal/util"
 )
 
-func (hg *HelperGenerator) FlattenersFromStruct(iface interface{}) map[string]string {
-	hg.init()
-	hg.generateFlattenersFromStruct(iface)
-	return hg.renderDeclarations()
+// FlattenersFromStruct generates flatteners of the struct & all nested structs by name,
+// it fails if two different types would share the same function name
+func (hg *HelperGenerator) FlattenersFromStruct(iface interface{}) (map[string]string, error) {
+	g := hg.newGeneration()
+	g.generateFlatteners(iface)
+	return g.renderDeclarations()
 }
 
-func (hg *HelperGenerator) generateFlattenersFromStruct(iface interface{}) string {
+func (g *generation) generateFlatteners(iface interface{}) {
+	if g.Backend.Framework {
+		g.generateFrameworkFlattener(reflect.TypeOf(iface))
+	} else {
+		g.collectShapes(reflect.TypeOf(iface))
+		g.generateFlattenersFromStruct(iface)
+	}
+}
+
+func (g *generation) generateFlattenersFromStruct(iface interface{}) string {
 	t := reflect.TypeOf(iface)
 	rawType := getRawType(t)
 
-	funcName := flattenerFuncNameFromType(t)
-	funcBody := hg.flattenerDeclarationBeginning(t)
+	funcName := g.funcName("flatten", t)
+
+	// nested flatteners start with variables of their own
+	mapVarName, mapValueName := g.mapVarName, g.mapValueName
+	g.mapVarName, g.mapValueName = g.OutputVarName, g.InputVarName
+	defer func() {
+		g.mapVarName, g.mapValueName = mapVarName, mapValueName
+	}()
+
+	funcBody := g.flattenerDeclarationBeginning(t)
+	returnsError := false
 
 	// Inline fields (typically those we never expect to be empty)
+	fields := ""
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
-		body, err := hg.inlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
+		body, fallible, err := g.inlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
 		if err != nil {
 			log.Printf("Skipping %s (inline): %s", sf.Name, err)
 			continue
 		}
-		funcBody += body
+		returnsError = returnsError || fallible
+		fields += body
 	}
 
 	// Outline fields (typically optional)
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
-		body, err := hg.outlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
+		body, fallible, err := g.outlineFlattenerField(sf.Name, sf.Type, iface, &sf, false)
 		if err != nil {
 			log.Printf("Skipping %s (outline): %s", sf.Name, err)
 			continue
 		}
-		funcBody += body
+		returnsError = returnsError || fallible
+		fields += body
 	}
 
-	funcBody += hg.flattenerDeclarationEnd(t)
+	if returnsError {
+		funcBody += "var err error\n"
+	}
+	funcBody += fields
+	funcBody += g.flattenerDeclarationEnd(t, returnsError)
 
-	hg.declarations[funcName] = &FunctionDeclaration{
-		PkgPath:   t.PkgPath(),
-		FuncName:  funcName,
-		Arguments: hg.InputVarName + " " + interfaceFromType(t),
-		Outputs:   mapInterfacesFromType(t),
-		FuncBody:  funcBody,
+	outputs := mapInterfacesFromType(t)
+	if returnsError {
+		outputs = "(" + outputs + ", error)"
 	}
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:      t.PkgPath(),
+		FuncName:     funcName,
+		Arguments:    g.InputVarName + " " + interfaceFromType(t),
+		Outputs:      outputs,
+		FuncBody:     funcBody,
+		returnsError: returnsError,
+	})
 
 	return funcName
 }
 
-func (hg *HelperGenerator) flattenerDeclarationBeginning(t reflect.Type) string {
+func (g *generation) flattenerDeclarationBeginning(t reflect.Type) string {
 	if t.Kind() == reflect.Slice {
-		body := hg.mapVarName + ` := make([]interface{}, len(in), len(in))
+		body := g.mapVarName + ` := make([]interface{}, len(in), len(in))
 for i, n := range in {
 m := make(map[string]interface{})
 `
-		hg.mapVarName = "m"
-		hg.mapValueName = "n"
+		g.mapVarName = "m"
+		g.mapValueName = "n"
 		return body
 	}
 
-	return hg.mapVarName + " := make(map[string]interface{})\n"
+	return g.mapVarName + " := make(map[string]interface{})\n"
 }
 
-func (hg *HelperGenerator) flattenerDeclarationEnd(t reflect.Type) string {
+func (g *generation) flattenerDeclarationEnd(t reflect.Type, returnsError bool) string {
+	nilErr := ""
+	if returnsError {
+		nilErr = ", nil"
+	}
+
 	if t.Kind() == reflect.Slice {
-		body := hg.OutputVarName + `[i] = ` + hg.mapVarName + `
+		body := g.OutputVarName + `[i] = ` + g.mapVarName + `
 }
-return ` + hg.OutputVarName
-		hg.mapVarName = ""
-		hg.mapValueName = ""
+return ` + g.OutputVarName + nilErr
+		g.mapVarName = ""
+		g.mapValueName = ""
 		return body
 	}
 
-	return `return []interface{}{` + hg.OutputVarName + `}`
+	return `return []interface{}{` + g.OutputVarName + `}` + nilErr
 }
 
-func (hg *HelperGenerator) inlineFlattenerField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, error) {
+// inlineFlattenerField returns assignment of the field & whether it's fallible
+// (i.e. the value comes from function returning error)
+func (g *generation) inlineFlattenerField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, bool, error) {
 	kind := u.DereferencePtrType(sfType).Kind()
 	s := &schema.Schema{}
 
 	if sf != nil {
 		var ok bool
-		kind, ok = hg.InlineFieldFilterFunc(iface, sf, kind, s)
+		kind, ok = g.InlineFieldFilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (inline filter)", sf.Name)
+			return "", false, fmt.Errorf("Skipping %q (inline filter)", sf.Name)
 		}
 	}
 
-	value, err := hg.flattenerFieldValue(kind, sf, sfName, sfType)
+	value, err := g.flattenerFieldValue(kind, sf, sfName, sfType)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	leftSide := fmt.Sprintf("%s[%q]", hg.OutputVarName, u.Underscore(sf.Name))
-	if hg.mapVarName != "" {
-		leftSide = fmt.Sprintf("%s[%q]", hg.mapVarName, u.Underscore(sf.Name))
+	leftSide := fmt.Sprintf("%s[%q]", g.OutputVarName, u.Underscore(sf.Name))
+	if g.mapVarName != "" {
+		leftSide = fmt.Sprintf("%s[%q]", g.mapVarName, u.Underscore(sf.Name))
 	}
 
-	return fmt.Sprintf("%s = %s\n", leftSide, value), nil
+	body, fallible := g.flattenerAssignment(leftSide, value)
+	return body, fallible, nil
 }
 
-func (hg *HelperGenerator) outlineFlattenerField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, error) {
+func (g *generation) outlineFlattenerField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, bool, error) {
 	kind := u.DereferencePtrType(sfType).Kind()
 	s := &schema.Schema{}
 
 	if sf != nil {
 		var ok bool
-		kind, ok = hg.OutlineFieldFilterFunc(iface, sf, kind, s)
+		kind, ok = g.OutlineFieldFilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (outline filter)", sf.Name)
+			return "", false, fmt.Errorf("Skipping %q (outline filter)", sf.Name)
 		}
 	}
 
-	value, err := hg.flattenerFieldValue(kind, sf, sfName, sfType)
+	value, err := g.flattenerFieldValue(kind, sf, sfName, sfType)
 	if err != nil {
-		return "", err
+		return "", false, err
 	}
 
-	leftSide := fmt.Sprintf("%s[%q]", hg.OutputVarName, u.Underscore(sf.Name))
-	if hg.mapVarName != "" {
-		leftSide = fmt.Sprintf("%s[%q]", hg.mapVarName, u.Underscore(sf.Name))
+	leftSide := fmt.Sprintf("%s[%q]", g.OutputVarName, u.Underscore(sf.Name))
+	if g.mapVarName != "" {
+		leftSide = fmt.Sprintf("%s[%q]", g.mapVarName, u.Underscore(sf.Name))
 	}
 
 	if s.Optional || s.Computed {
-		inputVarName := hg.InputVarName
-		if hg.mapValueName != "" {
-			inputVarName = hg.mapValueName
+		inputVarName := g.InputVarName
+		if g.mapValueName != "" {
+			inputVarName = g.mapValueName
 		}
-		emptyValue, err := emptyConditionForType(inputVarName, sf)
+		emptyValue, err := g.emptyConditionForType(inputVarName, sf)
 		if err != nil {
 			log.Printf("Unknown optional condition: %s", err)
 		}
+		assignment, fallible := g.flattenerAssignment(leftSide, value)
 		body := fmt.Sprintf("if %s {\n", emptyValue)
-		body += fmt.Sprintf("%s = %s\n", leftSide, value)
+		body += assignment
 		body += "}\n"
-		return body, nil
+		return body, fallible, nil
 	}
 
-	return fmt.Sprintf("%s = %s\n", leftSide, value), nil
+	body, fallible := g.flattenerAssignment(leftSide, value)
+	return body, fallible, nil
 }
 
-func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.StructField, sfName string, sfType reflect.Type) (string, error) {
-	inputVarName := hg.InputVarName
-	if hg.mapValueName != "" {
-		inputVarName = hg.mapValueName
+// flattenerAssignment returns assignment of the value & whether it's fallible
+func (g *generation) flattenerAssignment(leftSide, value string) (string, bool) {
+	if g.isFallible(value) {
+		return fallibleAssignment(leftSide, value, "nil", "err"), true
+	}
+	return fmt.Sprintf("%s = %s\n", leftSide, value), false
+}
+
+func (g *generation) flattenerFieldValue(kind reflect.Kind, sf *reflect.StructField, sfName string, sfType reflect.Type) (string, error) {
+	inputVarName := g.InputVarName
+	if g.mapValueName != "" {
+		inputVarName = g.mapValueName
+	}
+
+	if value, ok := g.converterFlattenerFieldValue(inputVarName, sf, sfType); ok {
+		return value, nil
+	}
+	if value, ok := g.protoFlattenerFieldValue(inputVarName, sf, sfType); ok {
+		return value, nil
+	}
+	if u.IsJSONType(sfType) {
+		return g.jsonFlattenerFieldValue(sfType, inputVarName+"."+sf.Name), nil
 	}
 
 	switch kind {
@@ -161,12 +220,17 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
 		// Primitive data types are easy
-		sfPtr := ""
-		if sfType.Kind() == reflect.Ptr {
-			sfPtr = "*"
-		}
-		return fmt.Sprintf("%s%s.%s", sfPtr, inputVarName, sf.Name), nil
+		return g.primitiveFlattenerFieldValue(inputVarName, sf, sfType), nil
 	case reflect.Map:
+		if u.DereferencePtrType(sfType).Key().Kind() != reflect.String {
+			return "", fmt.Errorf("Unable to process: %s %s (keys are not strings)", sfName, sfType.String())
+		}
+		if isComplexMap(sfType) {
+			return g.mapFlattenerFieldValue(inputVarName, sf, sfType)
+		}
+		if u.IsByteSlice(sfType.Elem()) {
+			return g.bytesMapFlattenerFieldValue(sf, sfType, inputVarName+"."+sf.Name)
+		}
 		// TODO: map[string]*string
 		// TODO: map[string]*string
 		// TODO: map[string]int
@@ -174,25 +238,16 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 		// TODO: map[string]float
 		return fmt.Sprintf("%s.%s", inputVarName, sf.Name), nil
 	case reflect.Slice:
+		if u.IsByteSlice(sfType) {
+			return bytesFlattenerFieldValue(sf, inputVarName+"."+sf.Name), nil
+		}
 		// TODO: s.Type == TypeSet
-		sliceOf := sfType.Elem()
-		switch sliceOf.Kind() {
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
-			// Slice of primitive data types
-			return fmt.Sprintf("%s.%s", inputVarName, sf.Name), nil
-		case reflect.Ptr:
-			ptrTo := sliceOf.Elem()
-			funcName := hg.primitivePtrSliceFlattenerForType(ptrTo, sfType)
-			return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
-		case reflect.Struct:
-			iface := reflect.New(sfType).Elem().Interface()
-			funcName := hg.generateFlattenersFromStruct(iface)
-			return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
+		if value, err := g.sliceFlattenerValue(sfType, inputVarName+"."+sf.Name); err == nil {
+			return value, nil
 		}
 	case reflect.Struct:
 		iface := reflect.New(sfType).Elem().Interface()
-		funcName := hg.generateFlattenersFromStruct(iface)
+		funcName := g.generateFlattenersFromStruct(iface)
 		return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
 	}
 
@@ -200,11 +255,45 @@ func (hg *HelperGenerator) flattenerFieldValue(kind reflect.Kind, sf *reflect.St
 	return "", fmt.Errorf("Unable to process: %s", f)
 }
 
-func (hg *HelperGenerator) primitivePtrSliceFlattenerForType(t reflect.Type, sfType reflect.Type) string {
+// sliceFlattenerValue returns the flattened value of the slice
+func (g *generation) sliceFlattenerValue(sliceType reflect.Type, value string) (string, error) {
+	sliceOf := sliceType.Elem()
+	switch sliceOf.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		// Slice of primitive data types
+		if g.CheckOverflow && mayOverflowToInt(sliceOf) {
+			return fmt.Sprintf("%s(%s)", g.typedSliceFlattener(sliceOf, false), value), nil
+		}
+		return value, nil
+	case reflect.Ptr:
+		ptrTo := sliceOf.Elem()
+		funcName := g.primitivePtrSliceFlattenerForType(ptrTo, sliceType)
+		return fmt.Sprintf("%s(%s)", funcName, value), nil
+	case reflect.Struct:
+		iface := reflect.New(sliceType).Elem().Interface()
+		funcName := g.generateFlattenersFromStruct(iface)
+		return fmt.Sprintf("%s(%s)", funcName, value), nil
+	}
+	return "", fmt.Errorf("Unable to process: %s", sliceType.String())
+}
+
+func (g *generation) primitivePtrSliceFlattenerForType(t reflect.Type, sfType reflect.Type) string {
 	switch t.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String, reflect.Bool:
+		if !isSchemaGoType(t) {
+			// sized & named types, e.g. []*int32 or []*v1.Capability
+			return g.typedSliceFlattener(t, true)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Int:
 		return "flattenIntSlice"
-	case reflect.Float32, reflect.Float64:
+	case reflect.Float64:
 		return "flattenFloatSlice"
 	case reflect.String:
 		return "flattenStringSlice"
@@ -212,14 +301,7 @@ func (hg *HelperGenerator) primitivePtrSliceFlattenerForType(t reflect.Type, sfT
 		return "flattenBoolSlice"
 	case reflect.Struct:
 		iface := reflect.New(sfType).Elem().Interface()
-		return hg.generateFlattenersFromStruct(iface)
+		return g.generateFlattenersFromStruct(iface)
 	}
 	return ""
 }
-
-func flattenerFuncNameFromType(t reflect.Type) string {
-	// pkg.TypeName
-	parts := strings.Split(t.String(), ".")
-	rawTypeName := parts[1]
-	return "flatten" + rawTypeName
-}
@@ -3,6 +3,7 @@ package helpergen
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
@@ -27,7 +28,10 @@ func TestFlattenersFromStruct_primitives(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -51,7 +55,10 @@ return []interface{}{att}
 	}
 
 	// Pointer
-	ptrOutput := hg.FlattenersFromStruct(&SimpleStruct{})
+	ptrOutput, err := hg.FlattenersFromStruct(&SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedPtrOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in *helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -92,7 +99,10 @@ func TestFlattenersFromSliceOfStructs_primitives(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct([]SimpleStruct{})
+	output, err := hg.FlattenersFromStruct([]SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in []helpergen.SimpleStruct) []interface{} {
 att := make([]interface{}, len(in), len(in))
@@ -137,7 +147,10 @@ func TestFlattenersFromStruct_ptrsToPrimitives(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -177,7 +190,10 @@ func TestFlattenersFromStruct_nestedSingleLevel(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -215,7 +231,10 @@ func TestFlattenersFromStruct_ptrNestedSingleLevel(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -249,7 +268,10 @@ func TestFlattenersFromStruct_primitiveSlice(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -277,7 +299,10 @@ func TestFlattenersFromStruct_primitivePtrSlice(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -308,7 +333,10 @@ func TestFlattenersFromStruct_sliceOfStructs(t *testing.T) {
 		OutputVarName: "att",
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -361,7 +389,10 @@ func TestFlattenerFromStruct_optionalPrimitives(t *testing.T) {
 		return k, false
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -413,7 +444,10 @@ func TestFlattenerFromStruct_optionalPtrToPrimitives(t *testing.T) {
 		return k, false
 	}
 
-	output := hg.FlattenersFromStruct(SimpleStruct{})
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
 	expectedOutput := map[string]string{
 		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
 att := make(map[string]interface{})
@@ -436,3 +470,144 @@ return []interface{}{att}
 		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
 	}
 }
+
+func TestFlattenersFromStruct_converters(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		Converters:    quantityRegistry(),
+	}
+
+	output, err := hg.FlattenersFromStruct(&ConvertedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenConvertedStruct": `func flattenConvertedStruct(in *helpergen.ConvertedStruct) []interface{} {
+att := make(map[string]interface{})
+att["created_at"] = in.CreatedAt.Format(time.RFC3339)
+att["expires_at"] = (*in.ExpiresAt).Format(time.RFC3339)
+att["timeout"] = in.Timeout.String()
+att["memory"] = in.Memory.String()
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenerFromStruct_optionalConverters(t *testing.T) {
+	hg := &HelperGenerator{
+		InlineFieldFilterFunc: rejectAllFilter,
+		OutlineFieldFilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			s.Optional = true
+			return k, true
+		},
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	type TimedStruct struct {
+		CreatedAt time.Time
+		ExpiresAt *time.Time
+		Timeout   time.Duration
+		Retries   int32
+	}
+	output, err := hg.FlattenersFromStruct(TimedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenTimedStruct": `func flattenTimedStruct(in helpergen.TimedStruct) []interface{} {
+att := make(map[string]interface{})
+if !in.CreatedAt.IsZero() {
+att["created_at"] = in.CreatedAt.Format(time.RFC3339)
+}
+if in.ExpiresAt != nil {
+att["expires_at"] = (*in.ExpiresAt).Format(time.RFC3339)
+}
+if in.Timeout != 0 {
+att["timeout"] = in.Timeout.String()
+}
+if in.Retries != 0 {
+att["retries"] = in.Retries
+}
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_nestedAfterSlice(t *testing.T) {
+	type NestedLabel struct {
+		Value string
+	}
+	type NestedSelector struct {
+		Match string
+	}
+	type NestedStruct struct {
+		Labels   []NestedLabel
+		Selector map[string]NestedSelector
+	}
+
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	output, err := hg.FlattenersFromStruct(NestedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `func flattenNestedSelector(in helpergen.NestedSelector) []interface{} {
+att := make(map[string]interface{})
+att["match"] = in.Match
+return []interface{}{att}
+}`
+	if output["flattenNestedSelector"] != expected {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expected, output["flattenNestedSelector"])
+	}
+}
+
+func TestFlattenersFromStruct_sizedPtrSlice(t *testing.T) {
+	type SimpleStruct struct {
+		SliceOfInt32   []*int32
+		SliceOfFloat32 []*float32
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+
+	output, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+att := make(map[string]interface{})
+att["slice_of_int32"] = flattenInt32Slice(in.SliceOfInt32)
+att["slice_of_float32"] = flattenFloat32Slice(in.SliceOfFloat32)
+return []interface{}{att}
+}`,
+		"flattenInt32Slice": `func flattenInt32Slice(in []*int32) []interface{} {
+out := make([]interface{}, len(in))
+for i, v := range in {
+out[i] = int(*v)
+}
+return out
+}`,
+		"flattenFloat32Slice": `func flattenFloat32Slice(in []*float32) []interface{} {
+out := make([]interface{}, len(in))
+for i, v := range in {
+out[i] = float64(*v)
+}
+return out
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
@@ -0,0 +1,353 @@
+package helpergen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// frameworkPrimitive describes how a primitive kind is stored in framework models
+type frameworkPrimitive struct {
+	// GoType is type returned by the accessor, e.g. int64
+	GoType string
+	// Accessor is method returning the Go value, e.g. ValueInt64
+	Accessor string
+	// ValueFunc creates the framework value, e.g. types.Int64Value
+	ValueFunc string
+	// NullFunc creates null value, e.g. types.Int64Null
+	NullFunc string
+	// AttrType is code of the attr.Type, e.g. types.Int64Type
+	AttrType string
+}
+
+var (
+	frameworkString = &frameworkPrimitive{"string", "ValueString", "types.StringValue", "types.StringNull", "types.StringType"}
+	frameworkInt64  = &frameworkPrimitive{"int64", "ValueInt64", "types.Int64Value", "types.Int64Null", "types.Int64Type"}
+	frameworkFloat  = &frameworkPrimitive{"float64", "ValueFloat64", "types.Float64Value", "types.Float64Null", "types.Float64Type"}
+	frameworkBool   = &frameworkPrimitive{"bool", "ValueBool", "types.BoolValue", "types.BoolNull", "types.BoolType"}
+)
+
+func frameworkPrimitiveForKind(k reflect.Kind) (*frameworkPrimitive, bool) {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return frameworkInt64, true
+	case reflect.Float32, reflect.Float64:
+		return frameworkFloat, true
+	case reflect.String:
+		return frameworkString, true
+	case reflect.Bool:
+		return frameworkBool, true
+	}
+	return nil, false
+}
+
+func frameworkPrimitiveForSchemaType(t schema.ValueType) *frameworkPrimitive {
+	switch t {
+	case schema.TypeInt:
+		return frameworkInt64
+	case schema.TypeFloat:
+		return frameworkFloat
+	case schema.TypeBool:
+		return frameworkBool
+	}
+	return frameworkString
+}
+
+// frameworkObjectType returns code of the object type of the model of the given struct
+func frameworkObjectType(t reflect.Type) string {
+	return fmt.Sprintf("types.ObjectType{AttrTypes: %s{}.AttrTypes()}", backends.ModelName(t))
+}
+
+// frameworkCollection returns name of the collection of the field as used by schemagen,
+// i.e. Set for slices & List for nested structs
+func frameworkCollection(t reflect.Type) string {
+	if t.Kind() == reflect.Slice {
+		return "Set"
+	}
+	if u.DereferencePtrType(t).Kind() == reflect.Map {
+		return "Map"
+	}
+	return "List"
+}
+
+// isFrameworkField is true for fields accepted by either of filters,
+// as there is no distinction between inline & outline fields in the framework
+func (g *generation) isFrameworkField(iface interface{}, sf *reflect.StructField) bool {
+	kind := u.DereferencePtrType(sf.Type).Kind()
+	if _, ok := g.InlineFieldFilterFunc(iface, sf, kind, &schema.Schema{}); ok {
+		return true
+	}
+	_, ok := g.OutlineFieldFilterFunc(iface, sf, kind, &schema.Schema{})
+	return ok
+}
+
+// generateFrameworkExpander generates function converting framework model into the struct
+func (g *generation) generateFrameworkExpander(t reflect.Type) string {
+	rawType := u.DereferencePtrType(t)
+	iface := reflect.New(rawType).Elem().Interface()
+	funcName := g.FuncNameFunc("expand", t)
+	if g.isDeclared(funcName, t) {
+		return funcName
+	}
+	// Declared early to stop recursion
+	g.declare(t, &FunctionDeclaration{FuncName: funcName})
+
+	ptr := ""
+	if t.Kind() == reflect.Ptr {
+		ptr = "&"
+	}
+	funcBody := "var diags diag.Diagnostics\n"
+	funcBody += "obj := " + ptr + rawType.String() + "{}\n"
+	for i := 0; i < rawType.NumField(); i++ {
+		sf := rawType.Field(i)
+		if !g.isFrameworkField(iface, &sf) {
+			continue
+		}
+		body, err := g.frameworkExpanderField(&sf)
+		if err != nil {
+			log.Printf("Skipping %s: %s", sf.Name, err)
+			continue
+		}
+		funcBody += fmt.Sprintf("if !m.%s.IsNull() && !m.%s.IsUnknown() {\n%s}\n", sf.Name, sf.Name, body)
+	}
+	funcBody += "return obj, diags"
+
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:   rawType.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "ctx context.Context, m " + backends.ModelName(t),
+		Outputs:   "(" + interfaceFromType(t) + ", diag.Diagnostics)",
+		FuncBody:  funcBody,
+	})
+	return funcName
+}
+
+func (g *generation) frameworkExpanderField(sf *reflect.StructField) (string, error) {
+	modelValue := "m." + sf.Name
+	rawType := u.DereferencePtrType(sf.Type)
+
+	if c, ok := g.lookupConverter(sf.Type); ok {
+		g.declareHelpers(c.Helpers, false)
+		fp := frameworkPrimitiveForSchemaType(c.SchemaType)
+		value := fmt.Sprintf("%s.%s()", modelValue, fp.Accessor)
+		if c.SchemaGoType() != fp.GoType {
+			value = fmt.Sprintf("%s(%s)", c.SchemaGoType(), value)
+		}
+		if _, ok := g.Converters.Lookup(sf.Type); !ok {
+			// pointer to the converted type
+			return fmt.Sprintf("v := %s\nobj.%s = &v\n", fmt.Sprintf(c.Expander, value), sf.Name), nil
+		}
+		return fmt.Sprintf("obj.%s = %s\n", sf.Name, fmt.Sprintf(c.Expander, value)), nil
+	}
+	if isProtoEnum(sf.Type) {
+		return "", fmt.Errorf("Protobuf enums are not supported by the framework backend")
+	}
+
+	if fp, ok := frameworkPrimitiveForKind(rawType.Kind()); ok {
+		value := fmt.Sprintf("%s.%s()", modelValue, fp.Accessor)
+		if rawType.String() != fp.GoType {
+			value = fmt.Sprintf("%s(%s)", rawType.String(), value)
+		}
+		if sf.Type.Kind() == reflect.Ptr {
+			castType := rawType.String()
+			value = fmt.Sprintf("ptrTo%s%s(%s)", strings.ToUpper(castType[:1]), castType[1:], value)
+		}
+		return fmt.Sprintf("obj.%s = %s\n", sf.Name, value), nil
+	}
+
+	switch rawType.Kind() {
+	case reflect.Map:
+		if _, ok := frameworkPrimitiveForKind(u.DereferencePtrType(rawType.Elem()).Kind()); !ok {
+			return "", fmt.Errorf("Unable to process: %s %s", sf.Name, sf.Type.String())
+		}
+		return fmt.Sprintf("diags.Append(%s.ElementsAs(ctx, &obj.%s, false)...)\n", modelValue, sf.Name), nil
+	case reflect.Slice:
+		elemType := rawType.Elem()
+		if _, ok := frameworkPrimitiveForKind(u.DereferencePtrType(elemType).Kind()); ok {
+			return fmt.Sprintf("diags.Append(%s.ElementsAs(ctx, &obj.%s, false)...)\n", modelValue, sf.Name), nil
+		}
+		if u.DereferencePtrType(elemType).Kind() != reflect.Struct {
+			break
+		}
+		funcName := g.generateFrameworkExpander(u.DereferencePtrType(elemType))
+		ref := ""
+		if elemType.Kind() == reflect.Ptr {
+			ref = "&"
+		}
+		return fmt.Sprintf(`var items []%s
+diags.Append(%s.ElementsAs(ctx, &items, false)...)
+obj.%s = make(%s, len(items))
+for i, item := range items {
+v, d := %s(ctx, item)
+diags.Append(d...)
+obj.%s[i] = %sv
+}
+`, backends.ModelName(elemType), modelValue, sf.Name, interfaceFromType(rawType), funcName, sf.Name, ref), nil
+	case reflect.Struct:
+		funcName := g.generateFrameworkExpander(rawType)
+		ref := ""
+		if sf.Type.Kind() == reflect.Ptr {
+			ref = "&"
+		}
+		return fmt.Sprintf(`var items []%s
+diags.Append(%s.ElementsAs(ctx, &items, false)...)
+if len(items) > 0 {
+v, d := %s(ctx, items[0])
+diags.Append(d...)
+obj.%s = %sv
+}
+`, backends.ModelName(rawType), modelValue, funcName, sf.Name, ref), nil
+	}
+
+	return "", fmt.Errorf("Unable to process: %s %s", sf.Name, sf.Type.String())
+}
+
+// generateFrameworkFlattener generates function converting the struct into framework model
+func (g *generation) generateFrameworkFlattener(t reflect.Type) string {
+	rawType := u.DereferencePtrType(t)
+	iface := reflect.New(rawType).Elem().Interface()
+	funcName := g.FuncNameFunc("flatten", t)
+	if g.isDeclared(funcName, t) {
+		return funcName
+	}
+	// Declared early to stop recursion
+	g.declare(t, &FunctionDeclaration{FuncName: funcName})
+
+	fields := ""
+	usesDiags := false
+	for i := 0; i < rawType.NumField(); i++ {
+		sf := rawType.Field(i)
+		if !g.isFrameworkField(iface, &sf) {
+			continue
+		}
+		body, diags, err := g.frameworkFlattenerField(&sf)
+		if err != nil {
+			log.Printf("Skipping %s: %s", sf.Name, err)
+			continue
+		}
+		fields += body
+		usesDiags = usesDiags || diags
+	}
+
+	funcBody := "var diags diag.Diagnostics\n"
+	if usesDiags {
+		funcBody = "var diags, d diag.Diagnostics\n"
+	}
+	funcBody += "m := " + backends.ModelName(t) + "{}\n" + fields + "return m, diags"
+
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:   rawType.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "ctx context.Context, " + g.InputVarName + " " + interfaceFromType(t),
+		Outputs:   "(" + backends.ModelName(t) + ", diag.Diagnostics)",
+		FuncBody:  funcBody,
+	})
+	return funcName
+}
+
+// frameworkFlattenerField returns code setting the field of the model
+// and whether it uses (pre-declared) diagnostics d
+func (g *generation) frameworkFlattenerField(sf *reflect.StructField) (string, bool, error) {
+	modelValue := "m." + sf.Name
+	value := g.InputVarName + "." + sf.Name
+	rawType := u.DereferencePtrType(sf.Type)
+
+	if c, ok := g.lookupConverter(sf.Type); ok {
+		fp := frameworkPrimitiveForSchemaType(c.SchemaType)
+		if _, ok := g.Converters.Lookup(sf.Type); !ok {
+			// pointer to the converted type
+			converted := fmt.Sprintf(c.Flattener, "(*"+value+")")
+			if c.SchemaGoType() != fp.GoType {
+				converted = fmt.Sprintf("%s(%s)", fp.GoType, converted)
+			}
+			return fmt.Sprintf(`%s = %s()
+if %s != nil {
+%s = %s(%s)
+}
+`, modelValue, fp.NullFunc, value, modelValue, fp.ValueFunc, converted), false, nil
+		}
+		converted := fmt.Sprintf(c.Flattener, value)
+		if c.SchemaGoType() != fp.GoType {
+			converted = fmt.Sprintf("%s(%s)", fp.GoType, converted)
+		}
+		return fmt.Sprintf("%s = %s(%s)\n", modelValue, fp.ValueFunc, converted), false, nil
+	}
+	if isProtoEnum(sf.Type) {
+		return "", false, fmt.Errorf("Protobuf enums are not supported by the framework backend")
+	}
+
+	if fp, ok := frameworkPrimitiveForKind(rawType.Kind()); ok {
+		if sf.Type.Kind() == reflect.Ptr {
+			converted := "*" + value
+			if rawType.String() != fp.GoType {
+				converted = fmt.Sprintf("%s(%s)", fp.GoType, converted)
+			}
+			return fmt.Sprintf(`%s = %s()
+if %s != nil {
+%s = %s(%s)
+}
+`, modelValue, fp.NullFunc, value, modelValue, fp.ValueFunc, converted), false, nil
+		}
+		if rawType.String() != fp.GoType {
+			value = fmt.Sprintf("%s(%s)", fp.GoType, value)
+		}
+		return fmt.Sprintf("%s = %s(%s)\n", modelValue, fp.ValueFunc, value), false, nil
+	}
+
+	switch rawType.Kind() {
+	case reflect.Map, reflect.Slice:
+		elemType := rawType.Elem()
+		collection := frameworkCollection(rawType)
+		if fp, ok := frameworkPrimitiveForKind(u.DereferencePtrType(elemType).Kind()); ok {
+			return fmt.Sprintf(`%s, d = types.%sValueFrom(ctx, %s, %s)
+diags.Append(d...)
+`, modelValue, collection, fp.AttrType, value), true, nil
+		}
+		if rawType.Kind() == reflect.Map || u.DereferencePtrType(elemType).Kind() != reflect.Struct {
+			break
+		}
+		funcName := g.generateFrameworkFlattener(u.DereferencePtrType(elemType))
+		deref := ""
+		if elemType.Kind() == reflect.Ptr {
+			deref = "*"
+		}
+		items := strings.ToLower(sf.Name[:1]) + sf.Name[1:] + "Items"
+		return fmt.Sprintf(`%s := make([]%s, len(%s))
+for i, v := range %s {
+%s[i], d = %s(ctx, %sv)
+diags.Append(d...)
+}
+%s, d = types.%sValueFrom(ctx, %s, %s)
+diags.Append(d...)
+`, items, backends.ModelName(elemType), value, value, items, funcName, deref,
+			modelValue, collection, frameworkObjectType(elemType), items), true, nil
+	case reflect.Struct:
+		funcName := g.generateFrameworkFlattener(rawType)
+		item := strings.ToLower(sf.Name[:1]) + sf.Name[1:] + "Item"
+		objectType := frameworkObjectType(rawType)
+		if sf.Type.Kind() == reflect.Ptr {
+			return fmt.Sprintf(`%s = types.ListNull(%s)
+if %s != nil {
+%s, d := %s(ctx, *%s)
+diags.Append(d...)
+%s, d = types.ListValueFrom(ctx, %s, []%s{%s})
+diags.Append(d...)
+}
+`, modelValue, objectType, value, item, funcName, value,
+				modelValue, objectType, backends.ModelName(rawType), item), false, nil
+		}
+		return fmt.Sprintf(`%s, d := %s(ctx, %s)
+diags.Append(d...)
+%s, d = types.ListValueFrom(ctx, %s, []%s{%s})
+diags.Append(d...)
+`, item, funcName, value, modelValue, objectType, backends.ModelName(rawType), item), true, nil
+	}
+
+	return "", false, fmt.Errorf("Unable to process: %s %s", sf.Name, sf.Type.String())
+}
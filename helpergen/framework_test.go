@@ -0,0 +1,162 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+type FrameworkPort struct {
+	Number   int32
+	Protocol string
+}
+
+type FrameworkService struct {
+	Name      string
+	Replicas  *int32
+	Tags      []string
+	Labels    map[string]string
+	CreatedAt time.Time
+	ExpiresAt *time.Time
+	Port      *FrameworkPort
+	Ports     []*FrameworkPort
+}
+
+func TestExpandersFromStruct_framework(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+		Backend:       backends.Framework,
+	}
+
+	output, err := hg.ExpandersFromStruct(&FrameworkService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandFrameworkService": `func expandFrameworkService(ctx context.Context, m FrameworkServiceModel) (*helpergen.FrameworkService, diag.Diagnostics) {
+var diags diag.Diagnostics
+obj := &helpergen.FrameworkService{}
+if !m.Name.IsNull() && !m.Name.IsUnknown() {
+obj.Name = m.Name.ValueString()
+}
+if !m.Replicas.IsNull() && !m.Replicas.IsUnknown() {
+obj.Replicas = ptrToInt32(int32(m.Replicas.ValueInt64()))
+}
+if !m.Tags.IsNull() && !m.Tags.IsUnknown() {
+diags.Append(m.Tags.ElementsAs(ctx, &obj.Tags, false)...)
+}
+if !m.Labels.IsNull() && !m.Labels.IsUnknown() {
+diags.Append(m.Labels.ElementsAs(ctx, &obj.Labels, false)...)
+}
+if !m.CreatedAt.IsNull() && !m.CreatedAt.IsUnknown() {
+obj.CreatedAt = expandRFC3339Time(m.CreatedAt.ValueString())
+}
+if !m.ExpiresAt.IsNull() && !m.ExpiresAt.IsUnknown() {
+v := expandRFC3339Time(m.ExpiresAt.ValueString())
+obj.ExpiresAt = &v
+}
+if !m.Port.IsNull() && !m.Port.IsUnknown() {
+var items []FrameworkPortModel
+diags.Append(m.Port.ElementsAs(ctx, &items, false)...)
+if len(items) > 0 {
+v, d := expandFrameworkPort(ctx, items[0])
+diags.Append(d...)
+obj.Port = &v
+}
+}
+if !m.Ports.IsNull() && !m.Ports.IsUnknown() {
+var items []FrameworkPortModel
+diags.Append(m.Ports.ElementsAs(ctx, &items, false)...)
+obj.Ports = make([]*helpergen.FrameworkPort, len(items))
+for i, item := range items {
+v, d := expandFrameworkPort(ctx, item)
+diags.Append(d...)
+obj.Ports[i] = &v
+}
+}
+return obj, diags
+}`,
+		"expandFrameworkPort": `func expandFrameworkPort(ctx context.Context, m FrameworkPortModel) (helpergen.FrameworkPort, diag.Diagnostics) {
+var diags diag.Diagnostics
+obj := helpergen.FrameworkPort{}
+if !m.Number.IsNull() && !m.Number.IsUnknown() {
+obj.Number = int32(m.Number.ValueInt64())
+}
+if !m.Protocol.IsNull() && !m.Protocol.IsUnknown() {
+obj.Protocol = m.Protocol.ValueString()
+}
+return obj, diags
+}`,
+		"expandRFC3339Time": `func expandRFC3339Time(v string) time.Time {
+t, err := time.Parse(time.RFC3339, v)
+if err != nil {
+log.Printf("ERROR: Unable to parse time: %s", err)
+}
+return t
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_framework(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+		Backend:       backends.Framework,
+	}
+
+	output, err := hg.FlattenersFromStruct(&FrameworkService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenFrameworkService": `func flattenFrameworkService(ctx context.Context, in *helpergen.FrameworkService) (FrameworkServiceModel, diag.Diagnostics) {
+var diags, d diag.Diagnostics
+m := FrameworkServiceModel{}
+m.Name = types.StringValue(in.Name)
+m.Replicas = types.Int64Null()
+if in.Replicas != nil {
+m.Replicas = types.Int64Value(int64(*in.Replicas))
+}
+m.Tags, d = types.SetValueFrom(ctx, types.StringType, in.Tags)
+diags.Append(d...)
+m.Labels, d = types.MapValueFrom(ctx, types.StringType, in.Labels)
+diags.Append(d...)
+m.CreatedAt = types.StringValue(in.CreatedAt.Format(time.RFC3339))
+m.ExpiresAt = types.StringNull()
+if in.ExpiresAt != nil {
+m.ExpiresAt = types.StringValue((*in.ExpiresAt).Format(time.RFC3339))
+}
+m.Port = types.ListNull(types.ObjectType{AttrTypes: FrameworkPortModel{}.AttrTypes()})
+if in.Port != nil {
+portItem, d := flattenFrameworkPort(ctx, *in.Port)
+diags.Append(d...)
+m.Port, d = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: FrameworkPortModel{}.AttrTypes()}, []FrameworkPortModel{portItem})
+diags.Append(d...)
+}
+portsItems := make([]FrameworkPortModel, len(in.Ports))
+for i, v := range in.Ports {
+portsItems[i], d = flattenFrameworkPort(ctx, *v)
+diags.Append(d...)
+}
+m.Ports, d = types.SetValueFrom(ctx, types.ObjectType{AttrTypes: FrameworkPortModel{}.AttrTypes()}, portsItems)
+diags.Append(d...)
+return m, diags
+}`,
+		"flattenFrameworkPort": `func flattenFrameworkPort(ctx context.Context, in helpergen.FrameworkPort) (FrameworkPortModel, diag.Diagnostics) {
+var diags diag.Diagnostics
+m := FrameworkPortModel{}
+m.Number = types.Int64Value(int64(in.Number))
+m.Protocol = types.StringValue(in.Protocol)
+return m, diags
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
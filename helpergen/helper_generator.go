@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	"github.com/radeksimko/terraform-gen/converters"
 )
 
 type FunctionDeclaration struct {
@@ -16,40 +19,97 @@ type FunctionDeclaration struct {
 	Arguments string
 	Outputs   string
 	FuncBody  string
+
+	// goType is the converted type, e.g. []v1.Container (nil for helpers of converters)
+	goType reflect.Type
+	// returnsError is true if error is the last of outputs
+	returnsError bool
 }
 
 type fieldFilterFunc func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool)
+type funcNameFunc func(prefix string, t reflect.Type) string
 
+// HelperGenerator holds configuration only, so it can be used for many types
+// (incl. from multiple goroutines), state of each generation is kept in generation
 type HelperGenerator struct {
 	InlineFieldFilterFunc  fieldFilterFunc
 	OutlineFieldFilterFunc fieldFilterFunc
 	InputVarName           string
 	OutputVarName          string
+	// Converters defines representation of special types (defaults to converters.NewBuiltinRegistry)
+	Converters *converters.Registry
+	// Backend is the plugin SDK targeted by generated code (defaults to backends.HelperSchema).
+	// Framework backend generates conversions between models (see schemagen) & structs.
+	Backend *backends.Backend
+	// FuncNameFunc names functions converting the type, prefix is either expand or flatten
+	// (defaults to TypeFuncName, see PackageQualifiedFuncName for types from many packages)
+	FuncNameFunc funcNameFunc
+	// CheckOverflow makes helpers return error instead of silently truncating
+	// numbers which don't fit into int of helper/schema or into the field (e.g. uint64)
+	CheckOverflow bool
+	// ReturnErrors makes expanders check types of attributes via comma-ok assertions
+	// & return error with path of the attribute (e.g. spec.0.ports.1.port) instead of panicking
+	ReturnErrors bool
+}
+
+// generation is the context of a single call generating helpers of a root struct
+type generation struct {
+	// HelperGenerator is a copy of the configuration with defaults filled in
+	*HelperGenerator
 
 	mapVarName   string
 	mapValueName string
 	declarations map[string]*FunctionDeclaration
+	collisions   []string
+	// shapes holds qualifiers (see shapeQualifier) of all shapes
+	// in which each struct is converted, e.g. "" & "SliceOf"
+	shapes map[reflect.Type]map[string]bool
 }
 
-func (hg *HelperGenerator) init() {
-	hg.declarations = make(map[string]*FunctionDeclaration)
-	if hg.InlineFieldFilterFunc == nil {
-		hg.InlineFieldFilterFunc = acceptAllFilter
+func (hg *HelperGenerator) newGeneration() *generation {
+	cfg := *hg
+	if cfg.InlineFieldFilterFunc == nil {
+		cfg.InlineFieldFilterFunc = acceptAllFilter
+	}
+	if cfg.OutlineFieldFilterFunc == nil {
+		cfg.OutlineFieldFilterFunc = rejectAllFilter
+	}
+	if cfg.Converters == nil {
+		cfg.Converters = converters.NewBuiltinRegistry()
 	}
-	if hg.OutlineFieldFilterFunc == nil {
-		hg.OutlineFieldFilterFunc = rejectAllFilter
+	if cfg.Backend == nil {
+		cfg.Backend = backends.HelperSchema
 	}
-	if hg.mapVarName == "" {
-		hg.mapVarName = hg.OutputVarName
+	if cfg.FuncNameFunc == nil {
+		cfg.FuncNameFunc = TypeFuncName
 	}
-	if hg.mapValueName == "" {
-		hg.mapValueName = hg.InputVarName
+	return &generation{
+		HelperGenerator: &cfg,
+		mapVarName:      cfg.OutputVarName,
+		mapValueName:    cfg.InputVarName,
+		declarations:    make(map[string]*FunctionDeclaration),
+		collisions:      make([]string, 0),
+		shapes:          make(map[reflect.Type]map[string]bool),
 	}
 }
 
-func (hg *HelperGenerator) renderDeclarations() map[string]string {
+func (g *generation) collisionsError() error {
+	if len(g.collisions) > 0 {
+		return fmt.Errorf("Function names collide (see FuncNameFunc): %s", strings.Join(g.collisions, ", "))
+	}
+	return nil
+}
+
+func (g *generation) renderDeclarations() (map[string]string, error) {
+	if err := g.collisionsError(); err != nil {
+		return nil, err
+	}
+	return renderDeclarations(g.declarations), nil
+}
+
+func renderDeclarations(declarations map[string]*FunctionDeclaration) map[string]string {
 	m := make(map[string]string)
-	for name, decl := range hg.declarations {
+	for name, decl := range declarations {
 		buf := bytes.NewBuffer([]byte{})
 		err := funcDeclTpl.Execute(buf, decl)
 		if err != nil {
@@ -60,18 +120,210 @@ func (hg *HelperGenerator) renderDeclarations() map[string]string {
 	return m
 }
 
-func emptyConditionForType(inputVarName string, sf *reflect.StructField) (string, error) {
+// declare adds declaration of the function converting the type (nil for converters' helpers),
+// unless the same name is already taken by a different signature, which is recorded as collision
+func (g *generation) declare(t reflect.Type, decl *FunctionDeclaration) {
+	decl.goType = t
+	if existing, ok := g.declarations[decl.FuncName]; ok && !sameSignature(existing, decl) {
+		// keep the first declaration, the collision is reported when rendering
+		g.collisions = append(g.collisions, collisionDescription(existing, decl))
+		return
+	}
+	g.declarations[decl.FuncName] = decl
+}
+
+// isDeclared is true if the function name is taken, collision is recorded if it's by a different type
+func (g *generation) isDeclared(funcName string, t reflect.Type) bool {
+	existing, ok := g.declarations[funcName]
+	if !ok {
+		return false
+	}
+	if decl := (&FunctionDeclaration{FuncName: funcName, goType: t}); !sameSignature(existing, decl) {
+		g.collisions = append(g.collisions, collisionDescription(existing, decl))
+	}
+	return true
+}
+
+// sameSignature is true if both functions convert the same type (incl. its shape, e.g. []Foo),
+// helpers of converters don't convert any type, so their arguments & outputs are compared
+func sameSignature(a, b *FunctionDeclaration) bool {
+	if a.goType != nil || b.goType != nil {
+		return a.goType == b.goType
+	}
+	return a.Arguments == b.Arguments && a.Outputs == b.Outputs
+}
+
+func collisionDescription(a, b *FunctionDeclaration) string {
+	return fmt.Sprintf("%s (%s and %s)", a.FuncName, typeDescription(a.goType), typeDescription(b.goType))
+}
+
+// typeDescription returns the type with full path of its package, e.g. []*k8s.io/api/core/v1.Container
+func typeDescription(t reflect.Type) string {
+	if t == nil {
+		return "helper"
+	}
+	rawType := getRawType(t)
+	return strings.TrimSuffix(t.String(), rawType.String()) + rawType.PkgPath() + "." + rawType.Name()
+}
+
+// shapeQualifier describes the shape in which the struct is converted,
+// i.e. "" (Foo), "PtrTo" (*Foo), "SliceOf" ([]Foo) or "SliceOfPtrTo" ([]*Foo)
+func shapeQualifier(t reflect.Type) string {
+	qualifier := ""
+	if t.Kind() == reflect.Slice {
+		qualifier, t = "SliceOf", t.Elem()
+	}
+	if t.Kind() == reflect.Ptr {
+		qualifier += "PtrTo"
+	}
+	return qualifier
+}
+
+// shapesByPreference lists shapes in order of preference for the unqualified function name
+var shapesByPreference = []string{"", "PtrTo", "SliceOf", "SliceOfPtrTo"}
+
+// collectShapes records shapes of the struct & all nested structs (regardless of filters),
+// so that functions can be named the same way no matter which shape is converted first
+func (g *generation) collectShapes(t reflect.Type) {
+	rawType := t
+	if rawType.Kind() == reflect.Slice {
+		rawType = rawType.Elem()
+	}
+	if rawType.Kind() == reflect.Ptr {
+		rawType = rawType.Elem()
+	}
+	if rawType.Kind() != reflect.Struct {
+		return
+	}
+	if _, ok := g.lookupConverter(t); ok {
+		return
+	}
+	shapes, visited := g.shapes[rawType]
+	if !visited {
+		shapes = make(map[string]bool)
+		g.shapes[rawType] = shapes
+	}
+	shapes[shapeQualifier(t)] = true
+	if visited {
+		return
+	}
+	for i := 0; i < rawType.NumField(); i++ {
+		sfType := rawType.Field(i).Type
+		if sfType.Kind() == reflect.Map {
+			sfType = sfType.Elem()
+		}
+		g.collectShapes(sfType)
+	}
+}
+
+// funcName returns name of the function converting the struct (see FuncNameFunc).
+// Structs converted in more shapes (e.g. Foo & []Foo) keep the name for the first one
+// of Foo, *Foo, []Foo & []*Foo, others are qualified, e.g. expandSliceOfFoo
+func (g *generation) funcName(prefix string, t reflect.Type) string {
+	name := g.FuncNameFunc(prefix, t)
+	qualifier := shapeQualifier(t)
+	shapes := g.shapes[getRawType(t)]
+	if !shapes[qualifier] {
+		return name
+	}
+	for _, preferred := range shapesByPreference {
+		if shapes[preferred] {
+			if preferred == qualifier {
+				return name
+			}
+			break
+		}
+	}
+	return prefix + qualifier + strings.TrimPrefix(name, prefix)
+}
+
+// TypeFuncName names functions after the type, e.g. expandObjectMeta
+func TypeFuncName(prefix string, t reflect.Type) string {
+	return prefix + getRawType(t).Name()
+}
+
+// PackageQualifiedFuncName names functions after the package & type,
+// e.g. expandV1beta1ObjectMeta, so same-named types from different packages don't collide
+func PackageQualifiedFuncName(prefix string, t reflect.Type) string {
+	rawType := getRawType(t)
+	pkgName := strings.TrimSuffix(rawType.String(), "."+rawType.Name())
+	if pkgName == "" {
+		return TypeFuncName(prefix, t)
+	}
+	return prefix + strings.ToUpper(pkgName[:1]) + pkgName[1:] + rawType.Name()
+}
+
+// declareHelpers adds helper functions used by converter expressions
+// (fallible variants are declared if available & requested)
+func (g *generation) declareHelpers(helpers []*converters.Helper, fallible bool) {
+	for _, h := range helpers {
+		if fallible && h.FallibleFuncBody != "" {
+			g.declare(nil, &FunctionDeclaration{
+				FuncName:     h.FuncName,
+				Arguments:    h.Arguments,
+				Outputs:      "(" + h.Outputs + ", error)",
+				FuncBody:     h.FallibleFuncBody,
+				returnsError: true,
+			})
+			continue
+		}
+		g.declare(nil, &FunctionDeclaration{
+			FuncName:  h.FuncName,
+			Arguments: h.Arguments,
+			Outputs:   h.Outputs,
+			FuncBody:  h.FuncBody,
+		})
+	}
+}
+
+// isFallible is true if the value is a call of a declared function returning error
+func (g *generation) isFallible(value string) bool {
+	i := strings.Index(value, "(")
+	if i < 0 {
+		return false
+	}
+	decl, ok := g.declarations[value[:i]]
+	return ok && decl.returnsError
+}
+
+// fallibleAssignment assigns value of the fallible call, returning the error
+// (along with the given result) from the generated function on failure
+func fallibleAssignment(leftSide, value, result, errValue string) string {
+	return fmt.Sprintf(`%s, err = %s
+if err != nil {
+return %s, %s
+}
+`, leftSide, value, result, errValue)
+}
+
+// wrapValue applies wrapper to the value; wrapper is either name
+// of a function or a format (containing %s) from a converter
+func wrapValue(wrapper, value string) string {
+	if strings.Contains(wrapper, "%s") {
+		return fmt.Sprintf(wrapper, value)
+	}
+	return fmt.Sprintf("%s(%s)", wrapper, value)
+}
+
+// emptyConditionForType returns condition which is true if the field is set,
+// converters (e.g. of time.Time) may provide their own
+func (g *generation) emptyConditionForType(inputVarName string, sf *reflect.StructField) (string, error) {
 	leftSide := inputVarName + "." + sf.Name
+	if c, ok := g.Converters.Lookup(sf.Type); ok && c.EmptyCondition != "" {
+		return fmt.Sprintf(c.EmptyCondition, leftSide), nil
+	}
 
 	switch sf.Type.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-		reflect.Float32, reflect.Float64, reflect.Bool:
-		val := reflect.Zero(sf.Type)
-		return fmt.Sprintf("%s != %v", leftSide, val.Interface()), nil
+		reflect.Float32, reflect.Float64:
+		// %v of the zero value isn't a literal for named types, e.g. 0s of time.Duration
+		return fmt.Sprintf("%s != 0", leftSide), nil
+	case reflect.Bool:
+		return fmt.Sprintf("%s != false", leftSide), nil
 	case reflect.String:
 		return fmt.Sprintf(`%s != ""`, leftSide), nil
-	case reflect.Ptr:
+	case reflect.Ptr, reflect.Interface:
 		return fmt.Sprintf("%s != nil", leftSide), nil
 	case reflect.Slice, reflect.Map:
 		return fmt.Sprintf("len(%s) > 0", leftSide), nil
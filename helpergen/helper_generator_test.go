@@ -0,0 +1,213 @@
+package helpergen
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type CollidingSpec struct {
+	Name string
+}
+
+func TestPackageQualifiedFuncName(t *testing.T) {
+	for _, iface := range []interface{}{time.Time{}, &time.Time{}, []*time.Time{}} {
+		given := PackageQualifiedFuncName("expand", reflect.TypeOf(iface))
+		if given != "expandTimeTime" {
+			t.Fatalf("Expected expandTimeTime for %T, given: %q", iface, given)
+		}
+	}
+	given := PackageQualifiedFuncName("flatten", reflect.TypeOf(CollidingSpec{}))
+	if given != "flattenHelpergenCollidingSpec" {
+		t.Fatalf("Expected flattenHelpergenCollidingSpec, given: %q", given)
+	}
+}
+
+func TestHelperGenerator_collision(t *testing.T) {
+	type First struct {
+		Spec CollidingSpec
+	}
+	type CollidingSpec struct {
+		Replicas int
+	}
+	type SimpleStruct struct {
+		First  First
+		Second *CollidingSpec
+	}
+
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+	}
+	_, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err == nil || !strings.Contains(err.Error(), "expandCollidingSpec") {
+		t.Fatalf("Expected collision of expandCollidingSpec, given: %v", err)
+	}
+	_, err = hg.FlattenersFromStruct(SimpleStruct{})
+	if err == nil || !strings.Contains(err.Error(), "flattenCollidingSpec") {
+		t.Fatalf("Expected collision of flattenCollidingSpec, given: %v", err)
+	}
+
+	localSpec := reflect.TypeOf(CollidingSpec{})
+	hg.FuncNameFunc = func(prefix string, t reflect.Type) string {
+		if getRawType(t) == localSpec {
+			return prefix + "LocalSpec"
+		}
+		return TypeFuncName(prefix, t)
+	}
+	output, err := hg.ExpandersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0)
+	for name := range output {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	expectedNames := []string{"expandCollidingSpec", "expandFirst", "expandLocalSpec", "expandSimpleStruct"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedNames, names)
+	}
+}
+
+type ShapedItem struct {
+	Name string
+}
+
+type ShapedList struct {
+	Many  []ShapedItem
+	One   ShapedItem
+	Ptr   *ShapedItem
+	ByKey map[string]*ShapedItem
+}
+
+func TestHelperGenerator_shapes(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	expanders, err := hg.ExpandersFromStruct(ShapedList{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flatteners, err := hg.FlattenersFromStruct(ShapedList{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each shape gets its own function, the value keeps the name regardless of order
+	expectedSignatures := map[string]string{
+		"expandShapedItem":            "func expandShapedItem(l []interface{}) helpergen.ShapedItem {",
+		"expandPtrToShapedItem":       "func expandPtrToShapedItem(l []interface{}) *helpergen.ShapedItem {",
+		"expandSliceOfShapedItem":     "func expandSliceOfShapedItem(l []interface{}) []helpergen.ShapedItem {",
+		"expandMapOfPtrToShapedItem":  "func expandMapOfPtrToShapedItem(l []interface{}) map[string]*helpergen.ShapedItem {",
+		"expandShapedList":            "func expandShapedList(l []interface{}) helpergen.ShapedList {",
+		"flattenShapedItem":           "func flattenShapedItem(cfg helpergen.ShapedItem) []interface{} {",
+		"flattenPtrToShapedItem":      "func flattenPtrToShapedItem(cfg *helpergen.ShapedItem) []interface{} {",
+		"flattenSliceOfShapedItem":    "func flattenSliceOfShapedItem(cfg []helpergen.ShapedItem) []interface{} {",
+		"flattenMapOfPtrToShapedItem": "func flattenMapOfPtrToShapedItem(cfg map[string]*helpergen.ShapedItem) []interface{} {",
+		"flattenShapedList":           "func flattenShapedList(cfg helpergen.ShapedList) []interface{} {",
+	}
+	signatures := make(map[string]string)
+	for _, output := range []map[string]string{expanders, flatteners} {
+		for name, code := range output {
+			signatures[name] = strings.SplitN(code, "\n", 2)[0]
+		}
+	}
+	if !reflect.DeepEqual(signatures, expectedSignatures) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSignatures, signatures)
+	}
+
+	expectedFields := `Many: expandSliceOfShapedItem(cfg["many"].([]interface{})),
+One: expandShapedItem(cfg["one"].([]interface{})),
+Ptr: expandPtrToShapedItem(cfg["ptr"].([]interface{})),
+ByKey: expandMapOfPtrToShapedItem(cfg["by_key"].([]interface{})),
+`
+	if !strings.Contains(expanders["expandShapedList"], expectedFields) {
+		t.Fatalf("Expected fields: %s\n\nGiven: %s\n", expectedFields, expanders["expandShapedList"])
+	}
+}
+
+type ReusedPort struct {
+	Number int
+}
+
+type ReusedService struct {
+	Name  string
+	Ports []ReusedPort
+}
+
+func TestHelperGenerator_reusable(t *testing.T) {
+	newGenerator := func() *HelperGenerator {
+		return &HelperGenerator{
+			InputVarName:  "in",
+			OutputVarName: "att",
+		}
+	}
+	expectedExpanders, err := newGenerator().ExpandersFromStruct(ReusedService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFlatteners, err := newGenerator().FlattenersFromStruct(ReusedService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hg := newGenerator()
+	for i := 0; i < 2; i++ {
+		expanders, err := hg.ExpandersFromStruct(ReusedService{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(expanders, expectedExpanders) {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedExpanders, expanders)
+		}
+		flatteners, err := hg.FlattenersFromStruct(ReusedService{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(flatteners, expectedFlatteners) {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFlatteners, flatteners)
+		}
+	}
+
+	// defaults are not written back into the configuration
+	if hg.InlineFieldFilterFunc != nil || hg.Converters != nil || hg.Backend != nil || hg.FuncNameFunc != nil {
+		t.Fatalf("Expected configuration to be left intact, given: %#v", hg)
+	}
+}
+
+func TestHelperGenerator_concurrent(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	expectedFlatteners, err := hg.FlattenersFromStruct(ReusedService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	results := make([]map[string]string, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// expanders in between must not affect flatteners
+			if i%2 == 0 {
+				hg.ExpandersFromStruct(ReusedService{})
+			}
+			results[i], _ = hg.FlattenersFromStruct(ReusedService{})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, flatteners := range results {
+		if !reflect.DeepEqual(flatteners, expectedFlatteners) {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFlatteners, flatteners)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// jsonExpanderFieldValue returns wrapper function & value of field held as JSON string,
+// which is unmarshalled unless it's json.RawMessage
+func (g *generation) jsonExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string) {
+	value := fmt.Sprintf("%s[%q].(string)", g.InputVarName, u.Underscore(sf.Name))
+	if u.IsRawJSON(sfType) {
+		return "json.RawMessage", value
+	}
+
+	funcName, outputs, declaration := "expandJSON", "interface{}", "var obj interface{}"
+	if sfType.Kind() == reflect.Map {
+		funcName, outputs, declaration = "expandJSONObject", "map[string]interface{}", "obj := make(map[string]interface{})"
+	}
+	body := declaration + `
+if err := json.Unmarshal([]byte(v), &obj); err != nil {
+log.Printf("ERROR: Unable to unmarshal JSON: %s", err)
+}
+return obj`
+	if g.ReturnErrors {
+		body = declaration + "\nerr := json.Unmarshal([]byte(v), &obj)\nreturn obj, err"
+		outputs = "(" + outputs + ", error)"
+	}
+	g.declare(nil, &FunctionDeclaration{
+		FuncName:     funcName,
+		Arguments:    "v string",
+		Outputs:      outputs,
+		FuncBody:     body,
+		returnsError: g.ReturnErrors,
+	})
+	return funcName, value
+}
+
+// jsonFlattenerFieldValue returns the flattened value of field held as JSON string
+func (g *generation) jsonFlattenerFieldValue(sfType reflect.Type, value string) string {
+	if u.IsRawJSON(sfType) {
+		return fmt.Sprintf("string(%s)", value)
+	}
+	g.declare(nil, &FunctionDeclaration{
+		FuncName:  "flattenJSON",
+		Arguments: "v interface{}",
+		Outputs:   "string",
+		FuncBody: `b, err := json.Marshal(v)
+if err != nil {
+log.Printf("ERROR: Unable to marshal JSON: %s", err)
+}
+return string(b)`,
+	})
+	return fmt.Sprintf("flattenJSON(%s)", value)
+}
@@ -0,0 +1,110 @@
+package helpergen
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type JSONStruct struct {
+	Any    interface{}
+	Object map[string]interface{}
+	Raw    json.RawMessage
+}
+
+func TestExpanderFromStruct_json(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	output, err := hg.ExpandersFromStruct(JSONStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandJSONStruct": `func expandJSONStruct(l []interface{}) helpergen.JSONStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.JSONStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.JSONStruct{
+Any: expandJSON(cfg["any"].(string)),
+Object: expandJSONObject(cfg["object"].(string)),
+Raw: json.RawMessage(cfg["raw"].(string)),
+}
+return obj
+}`,
+		"expandJSON": `func expandJSON(v string) interface{} {
+var obj interface{}
+if err := json.Unmarshal([]byte(v), &obj); err != nil {
+log.Printf("ERROR: Unable to unmarshal JSON: %s", err)
+}
+return obj
+}`,
+		"expandJSONObject": `func expandJSONObject(v string) map[string]interface{} {
+obj := make(map[string]interface{})
+if err := json.Unmarshal([]byte(v), &obj); err != nil {
+log.Printf("ERROR: Unable to unmarshal JSON: %s", err)
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+
+	hg.ReturnErrors = true
+	output, err = hg.ExpandersFromStruct(JSONStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedHelper := `func expandJSON(v string) (interface{}, error) {
+var obj interface{}
+err := json.Unmarshal([]byte(v), &obj)
+return obj, err
+}`
+	if output["expandJSON"] != expectedHelper {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedHelper, output["expandJSON"])
+	}
+}
+
+func TestFlattenerFromStruct_json(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		OutlineFieldFilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			s.Optional = true
+			return k, sf.Name == "Any"
+		},
+		InlineFieldFilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			return k, sf.Name != "Any"
+		},
+	}
+	output, err := hg.FlattenersFromStruct(JSONStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenJSONStruct": `func flattenJSONStruct(in helpergen.JSONStruct) []interface{} {
+att := make(map[string]interface{})
+att["object"] = flattenJSON(in.Object)
+att["raw"] = string(in.Raw)
+if in.Any != nil {
+att["any"] = flattenJSON(in.Any)
+}
+return []interface{}{att}
+}`,
+		"flattenJSON": `func flattenJSON(v interface{}) string {
+b, err := json.Marshal(v)
+if err != nil {
+log.Printf("ERROR: Unable to marshal JSON: %s", err)
+}
+return string(b)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
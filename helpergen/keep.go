@@ -0,0 +1,16 @@
+package helpergen
+
+import (
+	"github.com/radeksimko/terraform-gen/internal/keep"
+)
+
+// PreserveKept returns generated declarations with functions marked
+// by "terraform-gen:keep" comment in the existing source code
+// taken over from the existing code
+func PreserveKept(declarations map[string]string, existingSrc []byte) (map[string]string, error) {
+	kept, err := keep.Funcs(existingSrc)
+	if err != nil {
+		return nil, err
+	}
+	return keep.Merge(declarations, kept), nil
+}
@@ -0,0 +1,45 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPreserveKept(t *testing.T) {
+	type SimpleStruct struct {
+		MyString string
+	}
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	existingSrc := `package cattle
+
+// terraform-gen:keep
+func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+	return []interface{}{}
+}
+
+func flattenOther(in helpergen.Other) []interface{} {
+	return []interface{}{}
+}
+`
+
+	flatteners, err := hg.FlattenersFromStruct(SimpleStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := PreserveKept(flatteners, []byte(existingSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenSimpleStruct": `// terraform-gen:keep
+func flattenSimpleStruct(in helpergen.SimpleStruct) []interface{} {
+	return []interface{}{}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
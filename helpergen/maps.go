@@ -0,0 +1,234 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// Attributes of blocks representing map entries (see schemagen.MapKeyName & MapValueName)
+const (
+	mapKeyName   = "key"
+	mapValueName = "value"
+)
+
+// isJSONMap is true for maps represented by a JSON string (terraform-gen:"map=json")
+func isJSONMap(sf *reflect.StructField) bool {
+	return sf != nil && u.TagOptions(sf)["map"] == "json"
+}
+
+// isComplexMap is true for maps with struct or slice values, which are represented
+// by list of blocks (or JSON string) instead of TypeMap
+func isComplexMap(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+	valueKind := u.DereferencePtrType(t.Elem()).Kind()
+	return valueKind == reflect.Struct || (valueKind == reflect.Slice && !u.IsByteSlice(t.Elem()))
+}
+
+// mapFuncName returns name of the function converting the map, e.g. expandMapOfContainerPort
+// or flattenJSONMapOfSliceOfString
+func (g *generation) mapFuncName(prefix string, mapType reflect.Type, json bool) string {
+	valueType := mapType.Elem()
+	name := "MapOf" + typeNamePart(valueType)
+	if u.DereferencePtrType(valueType).Kind() == reflect.Struct {
+		name = "MapOf" + strings.TrimPrefix(g.FuncNameFunc(prefix, valueType), prefix)
+		if valueType.Kind() == reflect.Ptr {
+			name = "MapOfPtrTo" + strings.TrimPrefix(name, "MapOf")
+		}
+	}
+	if json {
+		name = "JSON" + name
+	}
+	return prefix + name
+}
+
+// typeNamePart returns name of the type usable in function names, e.g. SliceOfPtrString
+func typeNamePart(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Slice:
+		return "SliceOf" + typeNamePart(t.Elem())
+	case reflect.Ptr:
+		return "Ptr" + typeNamePart(t.Elem())
+	}
+	name := t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// mapExpanderFieldValue returns wrapper function & value for maps with struct or slice values
+func (g *generation) mapExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string, error) {
+	key := u.Underscore(sf.Name)
+	if isJSONMap(sf) {
+		funcName := g.mapFuncName("expand", sfType, true)
+		body := fmt.Sprintf(`obj := make(%s)
+if err := json.Unmarshal([]byte(v), &obj); err != nil {
+log.Printf("ERROR: Unable to unmarshal %s: %%s", err)
+}
+return obj`, sfType.String(), sfType.String())
+		outputs := sfType.String()
+		if g.ReturnErrors {
+			body = fmt.Sprintf(`obj := make(%s)
+err := json.Unmarshal([]byte(v), &obj)
+return obj, err`, sfType.String())
+			outputs = "(" + outputs + ", error)"
+		}
+		g.declare(sfType, &FunctionDeclaration{
+			PkgPath:      getRawType(sfType.Elem()).PkgPath(),
+			FuncName:     funcName,
+			Arguments:    "v string",
+			Outputs:      outputs,
+			FuncBody:     body,
+			returnsError: g.ReturnErrors,
+		})
+		return funcName, fmt.Sprintf("%s[%q].(string)", g.InputVarName, key), nil
+	}
+
+	valueType := sfType.Elem()
+	keyValue := fmt.Sprintf("cfg[%q].(string)", mapKeyName)
+	entry := ""
+	if g.ReturnErrors {
+		entry = fmt.Sprintf(`key, ok := %s
+if !ok {
+return obj, fmt.Errorf("%%d.%s: expected string, given %%T", i, cfg[%q])
+}
+`, keyValue, mapKeyName, mapKeyName)
+		keyValue = "key"
+	}
+
+	var value, valueErrorPath string
+	if u.DereferencePtrType(valueType).Kind() == reflect.Struct {
+		iface := reflect.New(valueType).Elem().Interface()
+		value = fmt.Sprintf("%s([]interface{}{cfg})", g.generateExpandersFromStruct(iface))
+		valueErrorPath = "%d.%s"
+	} else {
+		valueFunc := g.primitiveSliceExpanderForType(u.DereferencePtrType(valueType.Elem()), valueType)
+		if valueFunc == "" {
+			return "", "", fmt.Errorf("Unable to process: %s %s", sf.Name, sfType.String())
+		}
+		value = fmt.Sprintf("%s(cfg[%q].([]interface{}))", valueFunc, mapValueName)
+		if g.ReturnErrors {
+			entry += fmt.Sprintf(`value, ok := cfg[%q].([]interface{})
+if !ok {
+return obj, fmt.Errorf("%%d.%s: expected []interface{}, given %%T", i, cfg[%q])
+}
+`, mapValueName, mapValueName, mapValueName)
+			value = fmt.Sprintf("%s(value)", valueFunc)
+		}
+		valueErrorPath = "%d." + mapValueName + ".%s"
+	}
+
+	if g.isFallible(value) {
+		entry += fmt.Sprintf(`v, err := %s
+if err != nil {
+return obj, fmt.Errorf(%q, i, err)
+}
+obj[%s] = v`, value, valueErrorPath, keyValue)
+	} else {
+		entry += fmt.Sprintf("obj[%s] = %s", keyValue, value)
+	}
+
+	outputs, index, result := sfType.String(), "_", "obj"
+	returnsError := g.ReturnErrors || g.isFallible(value)
+	if returnsError {
+		outputs, index, result = "("+outputs+", error)", "i", "obj, nil"
+	}
+
+	funcName := g.mapFuncName("expand", sfType, false)
+	g.declare(sfType, &FunctionDeclaration{
+		PkgPath:   getRawType(valueType).PkgPath(),
+		FuncName:  funcName,
+		Arguments: "l []interface{}",
+		Outputs:   outputs,
+		FuncBody: fmt.Sprintf(`obj := make(%s, len(l))
+for %s, n := range l {
+%s%s
+}
+return %s`, sfType.String(), index, elementAssertion(returnsError), entry, result),
+		returnsError: returnsError,
+	})
+	return funcName, fmt.Sprintf("%s[%q].([]interface{})", g.InputVarName, key), nil
+}
+
+// mapFlattenerFieldValue is the flattener counterpart of mapExpanderFieldValue,
+// entries are sorted by keys, so that the list doesn't change between runs
+func (g *generation) mapFlattenerFieldValue(inputVarName string, sf *reflect.StructField, sfType reflect.Type) (string, error) {
+	if isJSONMap(sf) {
+		funcName := g.mapFuncName("flatten", sfType, true)
+		g.declare(sfType, &FunctionDeclaration{
+			PkgPath:   getRawType(sfType.Elem()).PkgPath(),
+			FuncName:  funcName,
+			Arguments: g.InputVarName + " " + sfType.String(),
+			Outputs:   "string",
+			FuncBody: fmt.Sprintf(`b, err := json.Marshal(%s)
+if err != nil {
+log.Printf("ERROR: Unable to marshal %s: %%s", err)
+}
+return string(b)`, g.InputVarName, sfType.String()),
+		})
+		return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
+	}
+
+	valueType := sfType.Elem()
+	item := fmt.Sprintf("%s[k]", g.InputVarName)
+	var entry string
+	returnsError := false
+	if u.DereferencePtrType(valueType).Kind() == reflect.Struct {
+		iface := reflect.New(valueType).Elem().Interface()
+		value := fmt.Sprintf("%s(%s)", g.generateFlattenersFromStruct(iface), item)
+		entry = fmt.Sprintf(`m := %s[0].(map[string]interface{})
+m[%q] = k`, value, mapKeyName)
+		if returnsError = g.isFallible(value); returnsError {
+			entry = fmt.Sprintf(`l, err := %s
+if err != nil {
+return nil, err
+}
+m := l[0].(map[string]interface{})
+m[%q] = k`, value, mapKeyName)
+		}
+	} else {
+		value, err := g.sliceFlattenerValue(valueType, item)
+		if err != nil {
+			return "", fmt.Errorf("Unable to process: %s %s", sf.Name, sfType.String())
+		}
+		if returnsError = g.isFallible(value); returnsError {
+			entry = fmt.Sprintf(`value, err := %s
+if err != nil {
+return nil, err
+}
+`, value)
+			value = "value"
+		}
+		entry += fmt.Sprintf(`m := map[string]interface{}{
+%q: k,
+%q: %s,
+}`, mapKeyName, mapValueName, value)
+	}
+	outputs, result := "[]interface{}", g.OutputVarName
+	if returnsError {
+		outputs, result = "([]interface{}, error)", g.OutputVarName+", nil"
+	}
+
+	funcName := g.mapFuncName("flatten", sfType, false)
+	g.declare(sfType, &FunctionDeclaration{
+		PkgPath:   getRawType(valueType).PkgPath(),
+		FuncName:  funcName,
+		Arguments: g.InputVarName + " " + sfType.String(),
+		Outputs:   outputs,
+		FuncBody: fmt.Sprintf(`keys := make([]string, 0, len(%s))
+for k := range %s {
+keys = append(keys, k)
+}
+sort.Strings(keys)
+%s := make([]interface{}, len(keys), len(keys))
+for i, k := range keys {
+%s
+%s[i] = m
+}
+return %s`, g.InputVarName, g.InputVarName, g.OutputVarName, entry, g.OutputVarName, result),
+		returnsError: returnsError,
+	})
+	return fmt.Sprintf("%s(%s.%s)", funcName, inputVarName, sf.Name), nil
+}
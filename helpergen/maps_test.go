@@ -0,0 +1,144 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+)
+
+type MapPort struct {
+	Number int
+}
+
+type MapStruct struct {
+	Ports   map[string]MapPort
+	Aliases map[string][]string
+	Raw     map[string][]string `terraform-gen:"map=json"`
+	// keys which aren't strings cannot be represented, so the field is skipped
+	ByID map[int]MapPort
+}
+
+func TestExpandersFromStruct_maps(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	output, err := hg.ExpandersFromStruct(MapStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandMapStruct": `func expandMapStruct(l []interface{}) helpergen.MapStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.MapStruct{}
+}
+in := l[0].(map[string]interface{})
+obj := helpergen.MapStruct{
+Ports: expandMapOfMapPort(in["ports"].([]interface{})),
+Aliases: expandMapOfSliceOfString(in["aliases"].([]interface{})),
+Raw: expandJSONMapOfSliceOfString(in["raw"].(string)),
+}
+return obj
+}`,
+		"expandMapPort": `func expandMapPort(l []interface{}) helpergen.MapPort {
+if len(l) == 0 || l[0] == nil {
+return helpergen.MapPort{}
+}
+in := l[0].(map[string]interface{})
+obj := helpergen.MapPort{
+Number: in["number"].(int),
+}
+return obj
+}`,
+		"expandMapOfMapPort": `func expandMapOfMapPort(l []interface{}) map[string]helpergen.MapPort {
+obj := make(map[string]helpergen.MapPort, len(l))
+for _, n := range l {
+cfg := n.(map[string]interface{})
+obj[cfg["key"].(string)] = expandMapPort([]interface{}{cfg})
+}
+return obj
+}`,
+		"expandMapOfSliceOfString": `func expandMapOfSliceOfString(l []interface{}) map[string][]string {
+obj := make(map[string][]string, len(l))
+for _, n := range l {
+cfg := n.(map[string]interface{})
+obj[cfg["key"].(string)] = sliceOfString(cfg["value"].([]interface{}))
+}
+return obj
+}`,
+		"expandJSONMapOfSliceOfString": `func expandJSONMapOfSliceOfString(v string) map[string][]string {
+obj := make(map[string][]string)
+if err := json.Unmarshal([]byte(v), &obj); err != nil {
+log.Printf("ERROR: Unable to unmarshal map[string][]string: %s", err)
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_maps(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+	}
+	output, err := hg.FlattenersFromStruct(MapStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenMapStruct": `func flattenMapStruct(in helpergen.MapStruct) []interface{} {
+att := make(map[string]interface{})
+att["ports"] = flattenMapOfMapPort(in.Ports)
+att["aliases"] = flattenMapOfSliceOfString(in.Aliases)
+att["raw"] = flattenJSONMapOfSliceOfString(in.Raw)
+return []interface{}{att}
+}`,
+		"flattenMapPort": `func flattenMapPort(in helpergen.MapPort) []interface{} {
+att := make(map[string]interface{})
+att["number"] = in.Number
+return []interface{}{att}
+}`,
+		"flattenMapOfMapPort": `func flattenMapOfMapPort(in map[string]helpergen.MapPort) []interface{} {
+keys := make([]string, 0, len(in))
+for k := range in {
+keys = append(keys, k)
+}
+sort.Strings(keys)
+att := make([]interface{}, len(keys), len(keys))
+for i, k := range keys {
+m := flattenMapPort(in[k])[0].(map[string]interface{})
+m["key"] = k
+att[i] = m
+}
+return att
+}`,
+		"flattenMapOfSliceOfString": `func flattenMapOfSliceOfString(in map[string][]string) []interface{} {
+keys := make([]string, 0, len(in))
+for k := range in {
+keys = append(keys, k)
+}
+sort.Strings(keys)
+att := make([]interface{}, len(keys), len(keys))
+for i, k := range keys {
+m := map[string]interface{}{
+"key": k,
+"value": in[k],
+}
+att[i] = m
+}
+return att
+}`,
+		"flattenJSONMapOfSliceOfString": `func flattenJSONMapOfSliceOfString(in map[string][]string) string {
+b, err := json.Marshal(in)
+if err != nil {
+log.Printf("ERROR: Unable to marshal map[string][]string: %s", err)
+}
+return string(b)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedOutput, output)
+	}
+}
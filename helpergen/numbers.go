@@ -0,0 +1,159 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// schemaGoType returns type of values stored by helper/schema for the kind,
+// i.e. TypeInt is always int & TypeFloat always float64
+func schemaGoType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float64"
+	}
+	return kind.String()
+}
+
+// mayOverflowFromInt is true if int from helper/schema may not fit into the type
+// (named types included, e.g. type Port uint16)
+func mayOverflowFromInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// mayOverflowToInt is true if the type may not fit into int of helper/schema
+func mayOverflowToInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// titledTypeName returns name of the type usable in names of helpers, e.g. Uint64 or Port
+func (g *generation) titledTypeName(t reflect.Type) string {
+	name := g.FuncNameFunc("", t)
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func isUnsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// primitiveExpanderFieldValue returns wrapper function & value of the primitive field,
+// the value is asserted to type stored by helper/schema & converted by the wrapper, e.g. int32(v)
+func (g *generation) primitiveExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string) {
+	t := u.DereferencePtrType(sfType)
+	value := fmt.Sprintf("%s[%q].(%s)", g.InputVarName, u.Underscore(sf.Name), schemaGoType(t.Kind()))
+
+	if g.CheckOverflow && mayOverflowFromInt(t) {
+		return g.checkedNumberExpander(sfType), value
+	}
+
+	conversion := ""
+	if t.String() != schemaGoType(t.Kind()) {
+		conversion = t.String()
+	}
+	if sfType.Kind() == reflect.Ptr {
+		castType := t.String()
+		ptrHelperFunc := "ptrTo" + strings.ToUpper(castType[:1]) + castType[1:]
+		if conversion != "" {
+			return fmt.Sprintf("%s(%s(%%s))", ptrHelperFunc, conversion), value
+		}
+		return ptrHelperFunc, value
+	}
+	return conversion, value
+}
+
+// primitiveFlattenerFieldValue returns the flattened value of the primitive field
+func (g *generation) primitiveFlattenerFieldValue(inputVarName string, sf *reflect.StructField, sfType reflect.Type) string {
+	sfPtr := ""
+	if sfType.Kind() == reflect.Ptr {
+		sfPtr = "*"
+	}
+	value := fmt.Sprintf("%s%s.%s", sfPtr, inputVarName, sf.Name)
+
+	t := u.DereferencePtrType(sfType)
+	if g.CheckOverflow && mayOverflowToInt(t) {
+		return fmt.Sprintf("%s(%s)", g.checkedNumberFlattener(t), value)
+	}
+	return value
+}
+
+// checkedNumberExpander declares helper converting int to the (pointer to) number,
+// failing if it doesn't fit, e.g. expandUint64 or expandPtrToUint64
+func (g *generation) checkedNumberExpander(sfType reflect.Type) string {
+	t := u.DereferencePtrType(sfType)
+	typeName := t.String()
+	titledName := g.titledTypeName(t)
+
+	condition := fmt.Sprintf("int(%s(v)) != v", typeName)
+	if isUnsigned(t.Kind()) {
+		condition = "v < 0 || " + condition
+	}
+	funcName := "expand" + titledName
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "v int",
+		Outputs:   fmt.Sprintf("(%s, error)", typeName),
+		FuncBody: fmt.Sprintf(`if %s {
+return 0, fmt.Errorf("%%d overflows %s", v)
+}
+return %s(v), nil`, condition, typeName, typeName),
+		returnsError: true,
+	})
+	if sfType.Kind() != reflect.Ptr {
+		return funcName
+	}
+
+	ptrFuncName := "expandPtrTo" + titledName
+	g.declare(sfType, &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  ptrFuncName,
+		Arguments: "v int",
+		Outputs:   fmt.Sprintf("(*%s, error)", typeName),
+		FuncBody: fmt.Sprintf(`n, err := %s(v)
+return &n, err`, funcName),
+		returnsError: true,
+	})
+	return ptrFuncName
+}
+
+// checkedNumberFlattener declares helper converting the number to int,
+// failing if it doesn't fit, e.g. flattenUint64
+func (g *generation) checkedNumberFlattener(t reflect.Type) string {
+	typeName := t.String()
+	condition := fmt.Sprintf("%s(int(v)) != v", typeName)
+	if isUnsigned(t.Kind()) {
+		condition = "int(v) < 0 || " + condition
+	}
+	funcName := "flatten" + g.titledTypeName(t)
+	g.declare(t, &FunctionDeclaration{
+		PkgPath:   t.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "v " + typeName,
+		Outputs:   "(int, error)",
+		FuncBody: fmt.Sprintf(`if %s {
+return 0, fmt.Errorf("%%d overflows int", v)
+}
+return int(v), nil`, condition),
+		returnsError: true,
+	})
+	return funcName
+}
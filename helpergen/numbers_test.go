@@ -0,0 +1,302 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+)
+
+type CheckedPort struct {
+	Number uint16
+}
+
+type CheckedService struct {
+	Name     string
+	Replicas *int32
+	Limit    uint64
+	Ports    []CheckedPort
+}
+
+func TestExpanderFromStruct_checkOverflow(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		CheckOverflow: true,
+	}
+	output, err := hg.ExpandersFromStruct(CheckedService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedNames := []string{"expandCheckedPort", "expandCheckedService", "expandInt32",
+		"expandPtrToInt32", "expandUint16", "expandUint64"}
+	if names := sortedKeys(output); !reflect.DeepEqual(names, expectedNames) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedNames, names)
+	}
+
+	expected := map[string]string{
+		"expandCheckedService": `func expandCheckedService(l []interface{}) (helpergen.CheckedService, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.CheckedService{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.CheckedService{
+Name: cfg["name"].(string),
+}
+obj.Replicas, err = expandPtrToInt32(cfg["replicas"].(int))
+if err != nil {
+return obj, fmt.Errorf("replicas: %s", err)
+}
+obj.Limit, err = expandUint64(cfg["limit"].(int))
+if err != nil {
+return obj, fmt.Errorf("limit: %s", err)
+}
+obj.Ports, err = expandCheckedPort(cfg["ports"].([]interface{}))
+if err != nil {
+return obj, fmt.Errorf("ports.%s", err)
+}
+return obj, nil
+}`,
+		"expandCheckedPort": `func expandCheckedPort(l []interface{}) ([]helpergen.CheckedPort, error) {
+if len(l) == 0 || l[0] == nil {
+return []helpergen.CheckedPort{}, nil
+}
+obj := make([]helpergen.CheckedPort, len(l), len(l))
+for i, n := range l {
+cfg, ok := n.(map[string]interface{})
+if !ok {
+return obj, fmt.Errorf("%d: expected map[string]interface{}, given %T", i, n)
+}
+var err error
+obj[i] = helpergen.CheckedPort{
+}
+obj[i].Number, err = expandUint16(cfg["number"].(int))
+if err != nil {
+return obj, fmt.Errorf("%d.number: %s", i, err)
+}
+}
+return obj, nil
+}`,
+		"expandUint64": `func expandUint64(v int) (uint64, error) {
+if v < 0 || int(uint64(v)) != v {
+return 0, fmt.Errorf("%d overflows uint64", v)
+}
+return uint64(v), nil
+}`,
+		"expandPtrToInt32": `func expandPtrToInt32(v int) (*int32, error) {
+n, err := expandInt32(v)
+return &n, err
+}`,
+	}
+	for name, code := range expected {
+		if output[name] != code {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", code, output[name])
+		}
+	}
+}
+
+func TestFlattenerFromStruct_checkOverflow(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		CheckOverflow: true,
+	}
+	output, err := hg.FlattenersFromStruct(CheckedService{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"flattenCheckedService": `func flattenCheckedService(in helpergen.CheckedService) ([]interface{}, error) {
+att := make(map[string]interface{})
+var err error
+att["name"] = in.Name
+att["replicas"] = *in.Replicas
+att["limit"], err = flattenUint64(in.Limit)
+if err != nil {
+return nil, err
+}
+att["ports"] = flattenCheckedPort(in.Ports)
+return []interface{}{att}, nil
+}`,
+		"flattenCheckedPort": `func flattenCheckedPort(in []helpergen.CheckedPort) []interface{} {
+att := make([]interface{}, len(in), len(in))
+for i, n := range in {
+m := make(map[string]interface{})
+m["number"] = n.Number
+att[i] = m
+}
+return att
+}`,
+		"flattenUint64": `func flattenUint64(v uint64) (int, error) {
+if int(v) < 0 || uint64(int(v)) != v {
+return 0, fmt.Errorf("%d overflows int", v)
+}
+return int(v), nil
+}`,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, output)
+	}
+}
+
+func TestExpanderFromStruct_namedPrimitives(t *testing.T) {
+	type Protocol string
+	type NamedStruct struct {
+		Protocol Protocol
+		Weight   *float32
+	}
+
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+	}
+	output, err := hg.ExpandersFromStruct(NamedStruct{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"expandNamedStruct": `func expandNamedStruct(l []interface{}) helpergen.NamedStruct {
+if len(l) == 0 || l[0] == nil {
+return helpergen.NamedStruct{}
+}
+cfg := l[0].(map[string]interface{})
+obj := helpergen.NamedStruct{
+Protocol: helpergen.Protocol(cfg["protocol"].(string)),
+Weight: ptrToFloat32(float32(cfg["weight"].(float64))),
+}
+return obj
+}`,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, output)
+	}
+}
+
+type PortNumber uint16
+
+type CheckedEndpoint struct {
+	Port  PortNumber
+	Ports []PortNumber
+	Sizes []*uint64
+}
+
+func TestExpanderFromStruct_checkOverflowNamedAndSlices(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "cfg",
+		OutputVarName: "obj",
+		CheckOverflow: true,
+	}
+	output, err := hg.ExpandersFromStruct(CheckedEndpoint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"expandCheckedEndpoint": `func expandCheckedEndpoint(l []interface{}) (helpergen.CheckedEndpoint, error) {
+if len(l) == 0 || l[0] == nil {
+return helpergen.CheckedEndpoint{}, nil
+}
+cfg := l[0].(map[string]interface{})
+var err error
+obj := helpergen.CheckedEndpoint{
+}
+obj.Port, err = expandPortNumber(cfg["port"].(int))
+if err != nil {
+return obj, fmt.Errorf("port: %s", err)
+}
+obj.Ports, err = sliceOfPortNumber(cfg["ports"].([]interface{}))
+if err != nil {
+return obj, fmt.Errorf("ports.%s", err)
+}
+obj.Sizes, err = sliceOfPtrUint64(cfg["sizes"].([]interface{}))
+if err != nil {
+return obj, fmt.Errorf("sizes.%s", err)
+}
+return obj, nil
+}`,
+		"expandPortNumber": `func expandPortNumber(v int) (helpergen.PortNumber, error) {
+if v < 0 || int(helpergen.PortNumber(v)) != v {
+return 0, fmt.Errorf("%d overflows helpergen.PortNumber", v)
+}
+return helpergen.PortNumber(v), nil
+}`,
+		"sliceOfPortNumber": `func sliceOfPortNumber(l []interface{}) ([]helpergen.PortNumber, error) {
+out := make([]helpergen.PortNumber, len(l))
+for i, v := range l {
+e, err := expandPortNumber(v.(int))
+if err != nil {
+return nil, fmt.Errorf("%d: %s", i, err)
+}
+out[i] = e
+}
+return out, nil
+}`,
+		"sliceOfPtrUint64": `func sliceOfPtrUint64(l []interface{}) ([]*uint64, error) {
+out := make([]*uint64, len(l))
+for i, v := range l {
+e, err := expandUint64(v.(int))
+if err != nil {
+return nil, fmt.Errorf("%d: %s", i, err)
+}
+out[i] = &e
+}
+return out, nil
+}`,
+		"expandUint64": `func expandUint64(v int) (uint64, error) {
+if v < 0 || int(uint64(v)) != v {
+return 0, fmt.Errorf("%d overflows uint64", v)
+}
+return uint64(v), nil
+}`,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, output)
+	}
+}
+
+func TestFlattenerFromStruct_checkOverflowNamedAndSlices(t *testing.T) {
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "att",
+		CheckOverflow: true,
+	}
+	output, err := hg.FlattenersFromStruct(CheckedEndpoint{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"flattenCheckedEndpoint": `func flattenCheckedEndpoint(in helpergen.CheckedEndpoint) ([]interface{}, error) {
+att := make(map[string]interface{})
+var err error
+att["port"] = in.Port
+att["ports"] = in.Ports
+att["sizes"], err = flattenUint64Slice(in.Sizes)
+if err != nil {
+return nil, err
+}
+return []interface{}{att}, nil
+}`,
+		"flattenUint64Slice": `func flattenUint64Slice(in []*uint64) ([]interface{}, error) {
+out := make([]interface{}, len(in))
+for i, v := range in {
+n, err := flattenUint64(*v)
+if err != nil {
+return nil, err
+}
+out[i] = n
+}
+return out, nil
+}`,
+		"flattenUint64": `func flattenUint64(v uint64) (int, error) {
+if int(v) < 0 || uint64(int(v)) != v {
+return 0, fmt.Errorf("%d overflows int", v)
+}
+return int(v), nil
+}`,
+	}
+	if !reflect.DeepEqual(output, expected) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, output)
+	}
+}
@@ -0,0 +1,63 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var protoEnumType = reflect.TypeOf((*protoreflect.Enum)(nil)).Elem()
+
+// ProtobufFieldFilter is a field filter for Go types generated by protoc-gen-go.
+// It skips internal fields (state, sizeCache, unknownFields, XXX_*) and oneof wrappers.
+func ProtobufFieldFilter(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+	if sf.PkgPath != "" || strings.HasPrefix(sf.Name, "XXX_") {
+		return k, false
+	}
+	if sf.Type.Kind() == reflect.Interface {
+		return k, false
+	}
+	return k, true
+}
+
+func isProtoEnum(t reflect.Type) bool {
+	return u.DereferencePtrType(t).Implements(protoEnumType)
+}
+
+// protoExpanderFieldValue returns wrapper function & value for enums, which are strings in the schema
+// (well-known types like Duration & Timestamp are handled by converters)
+func (g *generation) protoExpanderFieldValue(sf *reflect.StructField, sfType reflect.Type) (string, string, bool) {
+	if !isProtoEnum(sfType) {
+		return "", "", false
+	}
+
+	enumType := u.DereferencePtrType(sfType)
+	funcName := g.FuncNameFunc("expand", enumType)
+	body := fmt.Sprintf("return %s(%s_value[v])", enumType.String(), enumType.String())
+	if sfType.Kind() == reflect.Ptr {
+		funcName = "expandPtrTo" + strings.TrimPrefix(funcName, "expand")
+		body += ".Enum()"
+	}
+	g.declare(sfType, &FunctionDeclaration{
+		PkgPath:   enumType.PkgPath(),
+		FuncName:  funcName,
+		Arguments: "v string",
+		Outputs:   interfaceFromType(sfType),
+		FuncBody:  body,
+	})
+
+	value := fmt.Sprintf("%s[%q].(string)", g.InputVarName, u.Underscore(sf.Name))
+	return funcName, value, true
+}
+
+// protoFlattenerFieldValue is the flattener counterpart of protoExpanderFieldValue
+func (g *generation) protoFlattenerFieldValue(inputVarName string, sf *reflect.StructField, sfType reflect.Type) (string, bool) {
+	if !isProtoEnum(sfType) {
+		return "", false
+	}
+	return fmt.Sprintf("%s.%s.String()", inputVarName, sf.Name), true
+}
@@ -0,0 +1,114 @@
+package helpergen
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type isProtoCow_Kind interface {
+	isProtoCow_Kind()
+}
+
+// ProtoCow mimics struct generated by protoc-gen-go
+type ProtoCow struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Name            string
+	Type            descriptorpb.FieldDescriptorProto_Type
+	Label           *descriptorpb.FieldDescriptorProto_Label
+	MilkingInterval *durationpb.Duration
+	BornAt          *timestamppb.Timestamp
+	Nickname        *wrapperspb.StringValue
+	Weight          *wrapperspb.Int32Value
+	Kind            isProtoCow_Kind
+}
+
+func TestExpandersFromStruct_protobuf(t *testing.T) {
+	hg := &HelperGenerator{
+		InlineFieldFilterFunc: ProtobufFieldFilter,
+		InputVarName:          "in",
+		OutputVarName:         "obj",
+	}
+
+	output, err := hg.ExpandersFromStruct(&ProtoCow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"expandProtoCow": `func expandProtoCow(l []interface{}) *helpergen.ProtoCow {
+if len(l) == 0 || l[0] == nil {
+return &helpergen.ProtoCow{}
+}
+in := l[0].(map[string]interface{})
+obj := &helpergen.ProtoCow{
+Name: in["name"].(string),
+Type: expandFieldDescriptorProto_Type(in["type"].(string)),
+Label: expandPtrToFieldDescriptorProto_Label(in["label"].(string)),
+MilkingInterval: expandDuration(in["milking_interval"].(string)),
+BornAt: expandTimestamp(in["born_at"].(string)),
+Nickname: wrapperspb.String(in["nickname"].(string)),
+Weight: wrapperspb.Int32(int32(in["weight"].(int))),
+}
+return obj
+}`,
+		"expandFieldDescriptorProto_Type": `func expandFieldDescriptorProto_Type(v string) descriptorpb.FieldDescriptorProto_Type {
+return descriptorpb.FieldDescriptorProto_Type(descriptorpb.FieldDescriptorProto_Type_value[v])
+}`,
+		"expandPtrToFieldDescriptorProto_Label": `func expandPtrToFieldDescriptorProto_Label(v string) *descriptorpb.FieldDescriptorProto_Label {
+return descriptorpb.FieldDescriptorProto_Label(descriptorpb.FieldDescriptorProto_Label_value[v]).Enum()
+}`,
+		"expandDuration": `func expandDuration(v string) *durationpb.Duration {
+d, err := time.ParseDuration(v)
+if err != nil {
+log.Printf("ERROR: Unable to parse duration: %s", err)
+}
+return durationpb.New(d)
+}`,
+		"expandTimestamp": `func expandTimestamp(v string) *timestamppb.Timestamp {
+t, err := time.Parse(time.RFC3339, v)
+if err != nil {
+log.Printf("ERROR: Unable to parse time: %s", err)
+}
+return timestamppb.New(t)
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
+
+func TestFlattenersFromStruct_protobuf(t *testing.T) {
+	hg := &HelperGenerator{
+		InlineFieldFilterFunc: ProtobufFieldFilter,
+		InputVarName:          "in",
+		OutputVarName:         "att",
+	}
+
+	output, err := hg.FlattenersFromStruct(&ProtoCow{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedOutput := map[string]string{
+		"flattenProtoCow": `func flattenProtoCow(in *helpergen.ProtoCow) []interface{} {
+att := make(map[string]interface{})
+att["name"] = in.Name
+att["type"] = in.Type.String()
+att["label"] = in.Label.String()
+att["milking_interval"] = in.MilkingInterval.AsDuration().String()
+att["born_at"] = in.BornAt.AsTime().Format(time.RFC3339)
+att["nickname"] = in.Nickname.GetValue()
+att["weight"] = int(in.Weight.GetValue())
+return []interface{}{att}
+}`,
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("\nExpected: %s\n\nGiven:    %s", expectedOutput, output)
+	}
+}
@@ -0,0 +1,102 @@
+package helpergen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Session accumulates expanders & flatteners of several root structs
+// (e.g. Pod, Deployment & StatefulSet), so that helpers shared
+// by more roots are declared only once
+type Session struct {
+	Generator *HelperGenerator
+
+	roots        []string
+	declarations map[string]*FunctionDeclaration
+	dependencies map[string][]string
+}
+
+// SessionOutput holds helpers of all roots added to the session
+type SessionOutput struct {
+	// Files holds helpers used by a single root only, by the root (e.g. file name)
+	Files map[string]map[string]string
+	// Shared holds helpers used by more roots, to be placed in a common file
+	Shared map[string]string
+	// Dependencies lists names of all helpers (own & shared) used by each root
+	Dependencies map[string][]string
+}
+
+// NewSession starts session generating helpers via the given generator
+func NewSession(hg *HelperGenerator) *Session {
+	return &Session{
+		Generator:    hg,
+		roots:        make([]string, 0),
+		declarations: make(map[string]*FunctionDeclaration),
+		dependencies: make(map[string][]string),
+	}
+}
+
+// Add generates expanders & flatteners of the struct for the given root (e.g. file name)
+// and fails if any of them collides with a helper of a different type from another root
+func (s *Session) Add(root string, iface interface{}) error {
+	if _, ok := s.dependencies[root]; ok {
+		return fmt.Errorf("Root %q was already added", root)
+	}
+
+	generated := make(map[string]*FunctionDeclaration)
+	for _, generate := range []func(*generation, interface{}){(*generation).generateExpanders, (*generation).generateFlatteners} {
+		g := s.Generator.newGeneration()
+		generate(g, iface)
+		if err := g.collisionsError(); err != nil {
+			return fmt.Errorf("%s: %s", root, err)
+		}
+		for name, decl := range g.declarations {
+			if existing, ok := s.declarations[name]; ok && !sameSignature(existing, decl) {
+				return fmt.Errorf("%s: %s collides with helper of another root (%s and %s)",
+					root, name, typeDescription(existing.goType), typeDescription(decl.goType))
+			}
+			generated[name] = decl
+		}
+	}
+
+	names := make([]string, 0, len(generated))
+	for name, decl := range generated {
+		if _, ok := s.declarations[name]; !ok {
+			s.declarations[name] = decl
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.roots = append(s.roots, root)
+	s.dependencies[root] = names
+	return nil
+}
+
+// Output splits helpers of all roots into shared ones & those used by a single root
+func (s *Session) Output() *SessionOutput {
+	users := make(map[string]int, len(s.declarations))
+	for _, names := range s.dependencies {
+		for _, name := range names {
+			users[name]++
+		}
+	}
+
+	out := &SessionOutput{
+		Files:        make(map[string]map[string]string, len(s.roots)),
+		Shared:       make(map[string]string),
+		Dependencies: make(map[string][]string, len(s.roots)),
+	}
+	rendered := renderDeclarations(s.declarations)
+	for _, root := range s.roots {
+		out.Files[root] = make(map[string]string)
+		out.Dependencies[root] = s.dependencies[root]
+		for _, name := range s.dependencies[root] {
+			if users[name] > 1 {
+				out.Shared[name] = rendered[name]
+			} else {
+				out.Files[root][name] = rendered[name]
+			}
+		}
+	}
+	return out
+}
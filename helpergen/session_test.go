@@ -0,0 +1,133 @@
+package helpergen
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSession_sharedHelpers(t *testing.T) {
+	type SessionContainer struct {
+		Image string
+	}
+	type SessionPod struct {
+		Name       string
+		Containers []SessionContainer
+	}
+	type SessionJob struct {
+		Completions int
+		Containers  []SessionContainer
+	}
+
+	hg := &HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+	}
+	s := NewSession(hg)
+	if err := s.Add("structure_pod.go", SessionPod{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("structure_job.go", SessionJob{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add("structure_job.go", SessionJob{}); err == nil {
+		t.Fatal("Expected error for root added twice")
+	}
+	out := s.Output()
+
+	expectedDependencies := map[string][]string{
+		"structure_pod.go": {"expandSessionContainer", "expandSessionPod", "flattenSessionContainer", "flattenSessionPod"},
+		"structure_job.go": {"expandSessionContainer", "expandSessionJob", "flattenSessionContainer", "flattenSessionJob"},
+	}
+	if !reflect.DeepEqual(out.Dependencies, expectedDependencies) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedDependencies, out.Dependencies)
+	}
+
+	expectedFiles := map[string][]string{
+		"structure_pod.go": {"expandSessionPod", "flattenSessionPod"},
+		"structure_job.go": {"expandSessionJob", "flattenSessionJob"},
+	}
+	files := make(map[string][]string, 0)
+	for root, helpers := range out.Files {
+		files[root] = sortedKeys(helpers)
+	}
+	if !reflect.DeepEqual(files, expectedFiles) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFiles, files)
+	}
+
+	// shared helpers are the same as if generated separately
+	expanders, err := hg.ExpandersFromStruct(SessionPod{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flatteners, err := hg.FlattenersFromStruct(SessionPod{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedShared := map[string]string{
+		"expandSessionContainer":  expanders["expandSessionContainer"],
+		"flattenSessionContainer": flatteners["flattenSessionContainer"],
+	}
+	if !reflect.DeepEqual(out.Shared, expectedShared) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedShared, out.Shared)
+	}
+	if out.Files["structure_pod.go"]["flattenSessionPod"] != flatteners["flattenSessionPod"] {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", flatteners["flattenSessionPod"], out.Files["structure_pod.go"]["flattenSessionPod"])
+	}
+}
+
+func TestSession_collision(t *testing.T) {
+	type SessionPod struct {
+		Spec CollidingSpec
+	}
+	type CollidingSpec struct {
+		Replicas int
+	}
+	type SessionJob struct {
+		Spec CollidingSpec
+	}
+
+	s := NewSession(&HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+	})
+	if err := s.Add("structure_pod.go", SessionPod{}); err != nil {
+		t.Fatal(err)
+	}
+	err := s.Add("structure_job.go", SessionJob{})
+	if err == nil || !strings.Contains(err.Error(), "expandCollidingSpec") {
+		t.Fatalf("Expected collision of expandCollidingSpec, given: %v", err)
+	}
+}
+
+func TestSession_shapeCollision(t *testing.T) {
+	type SessionList struct {
+		Items []ShapedItem
+	}
+	type SessionSingle struct {
+		Item ShapedItem
+	}
+
+	s := NewSession(&HelperGenerator{
+		InputVarName:  "in",
+		OutputVarName: "obj",
+	})
+	if err := s.Add("structure_list.go", SessionList{}); err != nil {
+		t.Fatal(err)
+	}
+	// expandShapedItem of the first root returns []ShapedItem
+	err := s.Add("structure_single.go", SessionSingle{})
+	if err == nil || !strings.Contains(err.Error(), "expandShapedItem") {
+		t.Fatalf("Expected collision of expandShapedItem, given: %v", err)
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
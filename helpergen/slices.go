@@ -0,0 +1,114 @@
+package helpergen
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// isSchemaGoType is true if helper/schema stores values of the type as they are
+// (int, float64, string & bool), slices of others need typed converters
+func isSchemaGoType(t reflect.Type) bool {
+	return t.String() == schemaGoType(t.Kind())
+}
+
+// typedSliceExpander declares helper converting list of primitives to slice of the type
+// (or of pointers to it), e.g. sliceOfInt32 or sliceOfPtrUint16, elements are checked
+// if they may overflow (see CheckOverflow) or are of unexpected type (see ReturnErrors)
+func (g *generation) typedSliceExpander(t reflect.Type, ptr bool) string {
+	sliceType := reflect.SliceOf(t)
+	funcName := "sliceOf" + g.titledTypeName(t)
+	if ptr {
+		sliceType = reflect.SliceOf(reflect.PtrTo(t))
+		funcName = "sliceOfPtr" + g.titledTypeName(t)
+	}
+	schemaType := schemaGoType(t.Kind())
+
+	body := fmt.Sprintf("out := make(%s, len(l))\nfor i, v := range l {\n", sliceType.String())
+	value := fmt.Sprintf("v.(%s)", schemaType)
+	returnsError := g.ReturnErrors
+	if g.ReturnErrors {
+		body += fmt.Sprintf(`n, ok := v.(%s)
+if !ok {
+return nil, fmt.Errorf("%%d: expected %s, given %%T", i, v)
+}
+`, schemaType, schemaType)
+		value = "n"
+	}
+	if g.CheckOverflow && mayOverflowFromInt(t) {
+		body += fmt.Sprintf(`e, err := %s(%s)
+if err != nil {
+return nil, fmt.Errorf("%%d: %%s", i, err)
+}
+`, g.checkedNumberExpander(t), value)
+		value = "e"
+		returnsError = true
+	} else {
+		value = fmt.Sprintf("%s(%s)", t.String(), value)
+	}
+	if ptr {
+		if value != "e" {
+			body += fmt.Sprintf("e := %s\n", value)
+		}
+		value = "&e"
+	}
+	body += fmt.Sprintf("out[i] = %s\n}\n", value)
+
+	outputs := sliceType.String()
+	if returnsError {
+		outputs = "(" + outputs + ", error)"
+		body += "return out, nil"
+	} else {
+		body += "return out"
+	}
+	g.declare(sliceType, &FunctionDeclaration{
+		PkgPath:      t.PkgPath(),
+		FuncName:     funcName,
+		Arguments:    "l []interface{}",
+		Outputs:      outputs,
+		FuncBody:     body,
+		returnsError: returnsError,
+	})
+	return funcName
+}
+
+// typedSliceFlattener declares helper converting slice of pointers to the type
+// (or of the type itself, if it may overflow, see CheckOverflow) to list of primitives,
+// e.g. flattenInt32Slice for []*int32 or flattenSliceOfUint64 for []uint64
+func (g *generation) typedSliceFlattener(t reflect.Type, ptr bool) string {
+	sliceType := reflect.SliceOf(t)
+	funcName := "flattenSliceOf" + g.titledTypeName(t)
+	value := "v"
+	if ptr {
+		sliceType = reflect.SliceOf(reflect.PtrTo(t))
+		funcName = "flatten" + g.titledTypeName(t) + "Slice"
+		value = "*v"
+	}
+
+	body := "out := make([]interface{}, len(in))\nfor i, v := range in {\n"
+	returnsError := g.CheckOverflow && mayOverflowToInt(t)
+	if returnsError {
+		body += fmt.Sprintf(`n, err := %s(%s)
+if err != nil {
+return nil, err
+}
+out[i] = n
+}
+return out, nil`, g.checkedNumberFlattener(t), value)
+	} else {
+		body += fmt.Sprintf("out[i] = %s(%s)\n}\nreturn out", schemaGoType(t.Kind()), value)
+	}
+
+	outputs := "[]interface{}"
+	if returnsError {
+		outputs = "([]interface{}, error)"
+	}
+	g.declare(sliceType, &FunctionDeclaration{
+		PkgPath:      t.PkgPath(),
+		FuncName:     funcName,
+		Arguments:    "in " + sliceType.String(),
+		Outputs:      outputs,
+		FuncBody:     body,
+		returnsError: returnsError,
+	})
+	return funcName
+}
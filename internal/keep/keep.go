@@ -0,0 +1,131 @@
+package keep
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// Marker is to be placed in a comment inside a schema field or a function
+// which is supposed to survive regeneration
+const Marker = "terraform-gen:keep"
+
+// Fields returns source code of values of all top-level schema fields
+// (entries of outermost map[string]*schema.Schema literals) which contain the marker.
+// Marker placed in a nested schema keeps the whole top-level field.
+func Fields(src []byte) (map[string]string, error) {
+	fset, f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string, 0)
+	ast.Inspect(f, func(n ast.Node) bool {
+		cl, ok := n.(*ast.CompositeLit)
+		if !ok || !IsSchemaMapType(cl.Type) {
+			return true
+		}
+		for _, elt := range cl.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			key, ok := kv.Key.(*ast.BasicLit)
+			if !ok || key.Kind != token.STRING {
+				continue
+			}
+			name, err := strconv.Unquote(key.Value)
+			if err != nil {
+				continue
+			}
+			if containsMarker(f, kv.Value.Pos(), kv.Value.End()) {
+				fields[name] = source(fset, src, kv.Value.Pos(), kv.Value.End())
+			}
+		}
+		return false
+	})
+
+	return fields, nil
+}
+
+// Funcs returns source code of all function declarations (including doc comments)
+// which contain the marker
+func Funcs(src []byte) (map[string]string, error) {
+	fset, f, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := make(map[string]string, 0)
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		begin := fd.Pos()
+		if fd.Doc != nil {
+			begin = fd.Doc.Pos()
+		}
+		if containsMarker(f, begin, fd.End()) {
+			funcs[fd.Name.Name] = source(fset, src, begin, fd.End())
+		}
+	}
+
+	return funcs, nil
+}
+
+// Merge returns generated code (keyed by name of field or function)
+// with the kept code taken over as-is, regardless of the struct
+func Merge(generated, kept map[string]string) map[string]string {
+	merged := make(map[string]string, len(generated))
+	for name, content := range generated {
+		merged[name] = content
+	}
+	for name, content := range kept {
+		merged[name] = content
+	}
+	return merged
+}
+
+func parse(src []byte) (*token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	return fset, f, err
+}
+
+func containsMarker(f *ast.File, begin, end token.Pos) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() < begin || cg.End() > end {
+			continue
+		}
+		for _, c := range cg.List {
+			if strings.Contains(c.Text, Marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func source(fset *token.FileSet, src []byte, begin, end token.Pos) string {
+	return string(src[fset.Position(begin).Offset:fset.Position(end).Offset])
+}
+
+// IsSchemaMapType checks whether the expression is map[string]*schema.Schema
+func IsSchemaMapType(expr ast.Expr) bool {
+	mt, ok := expr.(*ast.MapType)
+	if !ok {
+		return false
+	}
+	if key, ok := mt.Key.(*ast.Ident); !ok || key.Name != "string" {
+		return false
+	}
+	star, ok := mt.Value.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "Schema"
+}
@@ -0,0 +1,121 @@
+package keep
+
+import (
+	"reflect"
+	"testing"
+)
+
+const keepTestSource = `package kubernetes
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var podSpecSchema = map[string]*schema.Schema{
+	"active_deadline_seconds": {
+		Type:     schema.TypeInt,
+		Optional: true,
+	},
+	"dns_policy": {
+		// terraform-gen:keep
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "ClusterFirst",
+	},
+	"container": {
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"image": {
+					// terraform-gen:keep
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	},
+}
+
+func flattenPodSpec(in v1.PodSpec) []interface{} {
+	att := make(map[string]interface{})
+	return []interface{}{att}
+}
+
+// expandPodSpec was tweaked manually
+// terraform-gen:keep
+func expandPodSpec(l []interface{}) v1.PodSpec {
+	return v1.PodSpec{}
+}
+`
+
+func TestFields(t *testing.T) {
+	fields, err := Fields([]byte(keepTestSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		"container": `{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"image": {
+					// terraform-gen:keep
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}`,
+		"dns_policy": `{
+		// terraform-gen:keep
+		Type:     schema.TypeString,
+		Optional: true,
+		Default:  "ClusterFirst",
+	}`,
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedFields, fields)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	funcs, err := Funcs([]byte(keepTestSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFuncs := map[string]string{
+		"expandPodSpec": `// expandPodSpec was tweaked manually
+// terraform-gen:keep
+func expandPodSpec(l []interface{}) v1.PodSpec {
+	return v1.PodSpec{}
+}`,
+	}
+	if !reflect.DeepEqual(funcs, expectedFuncs) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedFuncs, funcs)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	generated := map[string]string{
+		"name":     "generated name",
+		"replicas": "generated replicas",
+	}
+	kept := map[string]string{
+		"replicas": "kept replicas",
+		"custom":   "kept custom",
+	}
+	merged := Merge(generated, kept)
+	expectedMerged := map[string]string{
+		"name":     "generated name",
+		"replicas": "kept replicas",
+		"custom":   "kept custom",
+	}
+	if !reflect.DeepEqual(merged, expectedMerged) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedMerged, merged)
+	}
+	if generated["replicas"] != "generated replicas" {
+		t.Fatalf("Generated fields were modified: %q", generated)
+	}
+}
@@ -0,0 +1,22 @@
+package util
+
+import "reflect"
+
+// Encodings of byte slices in string attributes, e.g. `terraform-gen:"bytes=raw"`
+const (
+	Base64Encoding = "base64"
+	RawEncoding    = "raw"
+)
+
+// IsByteSlice is true for []byte & named byte slices (e.g. type Data []byte)
+func IsByteSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8
+}
+
+// BytesEncoding returns encoding of the byte slice field (Base64Encoding by default)
+func BytesEncoding(sf *reflect.StructField) string {
+	if sf != nil && TagOptions(sf)["bytes"] == RawEncoding {
+		return RawEncoding
+	}
+	return Base64Encoding
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsByteSlice(t *testing.T) {
+	type Data []byte
+	for _, iface := range []interface{}{[]byte{}, Data{}} {
+		if !IsByteSlice(reflect.TypeOf(iface)) {
+			t.Fatalf("Expected %T to be byte slice", iface)
+		}
+	}
+	for _, iface := range []interface{}{[]int{}, "", [4]byte{}} {
+		if IsByteSlice(reflect.TypeOf(iface)) {
+			t.Fatalf("Expected %T not to be byte slice", iface)
+		}
+	}
+}
+
+func TestBytesEncoding(t *testing.T) {
+	type SimpleStruct struct {
+		Encoded []byte
+		Raw     []byte `terraform-gen:"bytes=raw"`
+	}
+	st := reflect.TypeOf(SimpleStruct{})
+
+	encoded, _ := st.FieldByName("Encoded")
+	if given := BytesEncoding(&encoded); given != Base64Encoding {
+		t.Fatalf("Expected %q, given: %q", Base64Encoding, given)
+	}
+	raw, _ := st.FieldByName("Raw")
+	if given := BytesEncoding(&raw); given != RawEncoding {
+		t.Fatalf("Expected %q, given: %q", RawEncoding, given)
+	}
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+// IsJSONType is true for types represented by JSON strings,
+// i.e. interface{}, map[string]interface{} & json.RawMessage
+func IsJSONType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Interface && t.Elem().NumMethod() == 0
+	}
+	return IsRawJSON(t)
+}
+
+// IsRawJSON is true for json.RawMessage, which holds encoded JSON already
+func IsRawJSON(t reflect.Type) bool {
+	return t == rawMessageType
+}
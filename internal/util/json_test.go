@@ -0,0 +1,35 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestIsJSONType(t *testing.T) {
+	type SimpleStruct struct {
+		Any     interface{}
+		Object  map[string]interface{}
+		Raw     json.RawMessage
+		Labels  map[string]string
+		Data    []byte
+		Printer fmt.Stringer
+	}
+	st := reflect.TypeOf(SimpleStruct{})
+
+	expected := map[string]bool{
+		"Any":     true,
+		"Object":  true,
+		"Raw":     true,
+		"Labels":  false,
+		"Data":    false,
+		"Printer": false,
+	}
+	for name, isJSON := range expected {
+		sf, _ := st.FieldByName(name)
+		if given := IsJSONType(sf.Type); given != isJSON {
+			t.Fatalf("Expected %t for %s, given: %t", isJSON, sf.Type, given)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+)
+
+// TagKey is the key of struct tags recognised by schemagen & helpergen,
+// e.g. `terraform-gen:"default=80"`
+const TagKey = "terraform-gen"
+
+// TagOptions returns comma-separated options of the tag,
+// with empty value for options without one (flags)
+func TagOptions(sf *reflect.StructField) map[string]string {
+	opts := make(map[string]string, 0)
+	tag, ok := sf.Tag.Lookup(TagKey)
+	if !ok {
+		return opts
+	}
+	for _, opt := range strings.Split(tag, ",") {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+		parts := strings.SplitN(opt, "=", 2)
+		if len(parts) == 2 {
+			opts[parts[0]] = parts[1]
+		} else {
+			opts[parts[0]] = ""
+		}
+	}
+	return opts
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagOptions(t *testing.T) {
+	type SimpleStruct struct {
+		Tagged   string `json:"tagged" terraform-gen:"default=x, sensitive"`
+		Untagged string `json:"untagged"`
+	}
+	st := reflect.TypeOf(SimpleStruct{})
+
+	tagged, _ := st.FieldByName("Tagged")
+	expected := map[string]string{"default": "x", "sensitive": ""}
+	if given := TagOptions(&tagged); !reflect.DeepEqual(given, expected) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expected, given)
+	}
+
+	untagged, _ := st.FieldByName("Untagged")
+	if given := TagOptions(&untagged); len(given) != 0 {
+		t.Fatalf("Expected no options, given: %q", given)
+	}
+}
@@ -0,0 +1,92 @@
+package schemagen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+type getDefaultFunc func(iface interface{}, sf *reflect.StructField, s *schema.Schema) string
+
+// defaultInDocs matches phrases like Defaults to "ClusterFirst". or Defaults to 30.
+var defaultInDocs = regexp.MustCompile("(?i)\\bdefaults? (?:to|is) (?:\"([^\"]*)\"|'([^']*)'|`([^`]*)`|([^\\s,;]+))")
+
+// setDefault sets default value from (in order of precedence)
+// the struct tag, DefaultFunc or the description
+func (g *SchemaGenerator) setDefault(iface interface{}, sf *reflect.StructField, s *schema.Schema) {
+	raw, ok := u.TagOptions(sf)["default"]
+	if !ok && g.DefaultFunc != nil {
+		raw = g.DefaultFunc(iface, sf, s)
+		ok = raw != ""
+	}
+	if !ok {
+		raw, ok = defaultFromDocs(s.Description, s.Type)
+	}
+	if !ok {
+		return
+	}
+
+	// helper/schema rejects Default of computed fields, even if optional
+	if s.Required || s.Computed {
+		log.Printf("Ignoring default of %q (field is required or computed)", sf.Name)
+		return
+	}
+	v, err := typedDefault(s.Type, raw)
+	if err != nil {
+		log.Printf("Ignoring default of %q: %s", sf.Name, err)
+		return
+	}
+	s.Default = v
+}
+
+// defaultFromDocs finds default value in the description,
+// strings are only recognised when quoted
+func defaultFromDocs(docs string, t schema.ValueType) (string, bool) {
+	m := defaultInDocs.FindStringSubmatch(docs)
+	if m == nil {
+		return "", false
+	}
+	for _, quoted := range m[1:4] {
+		if quoted != "" {
+			return quoted, true
+		}
+	}
+	if t == schema.TypeString {
+		return "", false
+	}
+	return strings.TrimSuffix(m[4], "."), true
+}
+
+// typedDefault converts raw default value to the type of the field
+func typedDefault(t schema.ValueType, raw string) (interface{}, error) {
+	switch t {
+	case schema.TypeInt:
+		return strconv.Atoi(raw)
+	case schema.TypeFloat:
+		return strconv.ParseFloat(raw, 64)
+	case schema.TypeBool:
+		return strconv.ParseBool(raw)
+	case schema.TypeString:
+		return raw, nil
+	}
+	return nil, fmt.Errorf("Default is not supported for %s", t)
+}
+
+// defaultLiteral renders default value as Go literal of the right type
+func defaultLiteral(v interface{}) string {
+	switch d := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return formatFloat(d)
+	case string:
+		return strconv.Quote(d)
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,97 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGenerateField_defaults(t *testing.T) {
+	type SimpleStruct struct {
+		Port      int `terraform-gen:"default=8080"`
+		Ratio     float64
+		Enabled   bool
+		DNSPolicy string
+		Name      string
+		Replicas  int `terraform-gen:"default=three"`
+		Namespace string
+		Weight    float64
+		UID       string `terraform-gen:"default=none"`
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		docs := map[string]string{
+			"Port":      "Port to listen on. Defaults to 80.",
+			"Enabled":   "Whether it's enabled. Defaults to true.",
+			"DNSPolicy": `Set DNS policy for containers within the pod. Defaults to "ClusterFirst".`,
+			"Name":      "Name of the object. Required.",
+			"Namespace": "Namespace, defaults to the namespace of the parent.",
+			"Weight":    "Weight. Default is 1.",
+		}
+		return docs[sf.Name]
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		if sf.Name == "Name" {
+			s.Required = true
+		} else if sf.Name == "UID" {
+			s.Optional = true
+			s.Computed = true
+		} else {
+			s.Optional = true
+		}
+		return k, true
+	}
+	defaultF := func(iface interface{}, sf *reflect.StructField, s *schema.Schema) string {
+		if sf.Name == "Ratio" || sf.Name == "Name" {
+			return "0.5"
+		}
+		return ""
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF, DefaultFunc: defaultF}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"port":       "{\nType: schema.TypeInt,\nDescription: \"Port to listen on. Defaults to 80.\",\nOptional: true,\nDefault: 8080,\n}",
+		"ratio":      "{\nType: schema.TypeFloat,\nOptional: true,\nDefault: 0.5,\n}",
+		"enabled":    "{\nType: schema.TypeBool,\nDescription: \"Whether it's enabled. Defaults to true.\",\nOptional: true,\nDefault: true,\n}",
+		"dns_policy": "{\nType: schema.TypeString,\nDescription: \"Set DNS policy for containers within the pod. Defaults to \\\"ClusterFirst\\\".\",\nOptional: true,\nDefault: \"ClusterFirst\",\n}",
+		"name":       "{\nType: schema.TypeString,\nDescription: \"Name of the object. Required.\",\nRequired: true,\n}",
+		"replicas":   "{\nType: schema.TypeInt,\nOptional: true,\n}",
+		"namespace":  "{\nType: schema.TypeString,\nDescription: \"Namespace, defaults to the namespace of the parent.\",\nOptional: true,\n}",
+		"weight":     "{\nType: schema.TypeFloat,\nDescription: \"Weight. Default is 1.\",\nOptional: true,\nDefault: 1.0,\n}",
+		"uid":        "{\nType: schema.TypeString,\nOptional: true,\nComputed: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	s := g.SchemaFromStruct(&SimpleStruct{})
+	if s["weight"].Default != 1.0 {
+		t.Fatalf("Expected float64 default, given: %#v", s["weight"].Default)
+	}
+	if s["port"].Default != 8080 {
+		t.Fatalf("Expected int default, given: %#v", s["port"].Default)
+	}
+}
+
+func TestDefaultFromDocs(t *testing.T) {
+	cases := []struct {
+		Docs     string
+		Type     schema.ValueType
+		Expected string
+		Found    bool
+	}{
+		{"Defaults to 30 seconds.", schema.TypeInt, "30", true},
+		{"Defaults to 1.5.", schema.TypeFloat, "1.5", true},
+		{"Default to false.", schema.TypeBool, "false", true},
+		{"Defaults to `Always`.", schema.TypeString, "Always", true},
+		{"Defaults to Always.", schema.TypeString, "", false},
+		{"No default here.", schema.TypeInt, "", false},
+	}
+	for _, tc := range cases {
+		given, found := defaultFromDocs(tc.Docs, tc.Type)
+		if given != tc.Expected || found != tc.Found {
+			t.Fatalf("Expected %q (%t) for %q, given: %q (%t)", tc.Expected, tc.Found, tc.Docs, given, found)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package schemagen
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"reflect"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+// frameworkType describes representation of a schema type in terraform-plugin-framework
+type frameworkType struct {
+	// Name is used in names of attributes & plan modifiers, e.g. String
+	Name string
+	// Pkg is prefix of packages with defaults & plan modifiers, e.g. string
+	Pkg string
+	// AttrType is code of the attr.Type of primitives, e.g. types.StringType
+	AttrType string
+	// ModelType is type of the field in models, e.g. types.String
+	ModelType string
+}
+
+var frameworkTypes = map[schema.ValueType]*frameworkType{
+	schema.TypeString: {Name: "String", Pkg: "string", AttrType: "types.StringType", ModelType: "types.String"},
+	schema.TypeInt:    {Name: "Int64", Pkg: "int64", AttrType: "types.Int64Type", ModelType: "types.Int64"},
+	schema.TypeFloat:  {Name: "Float64", Pkg: "float64", AttrType: "types.Float64Type", ModelType: "types.Float64"},
+	schema.TypeBool:   {Name: "Bool", Pkg: "bool", AttrType: "types.BoolType", ModelType: "types.Bool"},
+	schema.TypeList:   {Name: "List", Pkg: "list", ModelType: "types.List"},
+	schema.TypeSet:    {Name: "Set", Pkg: "set", ModelType: "types.Set"},
+	schema.TypeMap:    {Name: "Map", Pkg: "map", ModelType: "types.Map"},
+}
+
+func (f *field) frameworkType() (*frameworkType, error) {
+	ft, ok := frameworkTypes[f.Schema.Type]
+	if !ok {
+		return nil, fmt.Errorf("%s is not supported by the framework backend", f.Schema.Type)
+	}
+	return ft, nil
+}
+
+// isFrameworkBlock is true for nested blocks which can be configured,
+// computed ones are nested attributes, because blocks cannot be computed
+func (f *field) isFrameworkBlock() bool {
+	_, ok := f.Elem.(block)
+	return ok && !(f.Schema.Computed && !f.Schema.Optional)
+}
+
+// frameworkCode returns code of the attribute or block, e.g. schema.StringAttribute{...}
+// ValidateFunc, ConflictsWith & ExactlyOneOf are not rendered (framework uses validators instead).
+// inComputed is true for fields nested in a computed attribute, which are computed too.
+func (f *field) frameworkCode(inComputed bool) (string, error) {
+	ft, err := f.frameworkType()
+	if err != nil {
+		return "", err
+	}
+	s := f.Schema
+
+	data := &frameworkFieldData{
+		Kind:        ft.Name + "Attribute",
+		Description: s.Description,
+		Required:    s.Required,
+		Optional:    s.Optional,
+		// Defaults are only allowed for computed attributes
+		Computed:  s.Computed || s.Default != nil,
+		Sensitive: s.Sensitive,
+	}
+	if inComputed {
+		data.Required, data.Optional, data.Computed = false, false, true
+	} else if s.ForceNew {
+		data.PlanModifiers = fmt.Sprintf("[]planmodifier.%s{\n%splanmodifier.RequiresReplace(),\n}", ft.Name, ft.Pkg)
+	}
+
+	switch e := f.Elem.(type) {
+	case block:
+		asAttribute := inComputed || !f.isFrameworkBlock()
+		data.NestedObject, err = e.frameworkObjectCode(asAttribute)
+		if err != nil {
+			return "", err
+		}
+		if asAttribute {
+			data.Kind = ft.Name + "NestedAttribute"
+			break
+		}
+		data.Kind = ft.Name + "NestedBlock"
+		data.Required, data.Optional, data.Computed, data.Sensitive = false, false, false, false
+		data.Validators = f.frameworkBlockValidators(ft)
+	case *field:
+		data.ElementType, err = e.frameworkAttrType()
+		if err != nil {
+			return "", err
+		}
+	default:
+		if s.Type == schema.TypeMap {
+			data.ElementType = "types.StringType"
+		}
+		if s.Default != nil && !inComputed {
+			data.Default = fmt.Sprintf("%sdefault.Static%s(%s)", ft.Pkg, ft.Name, defaultLiteral(s.Default))
+		}
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err = frameworkFieldTemplate.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// frameworkBlockValidators replaces Required & MaxItems, which blocks don't have
+func (f *field) frameworkBlockValidators(ft *frameworkType) string {
+	validators := ""
+	if f.Schema.Required {
+		validators += fmt.Sprintf("%svalidator.IsRequired(),\n", ft.Pkg)
+	}
+	if f.Schema.MinItems > 0 {
+		validators += fmt.Sprintf("%svalidator.SizeAtLeast(%d),\n", ft.Pkg, f.Schema.MinItems)
+	}
+	if f.Schema.MaxItems > 0 {
+		validators += fmt.Sprintf("%svalidator.SizeAtMost(%d),\n", ft.Pkg, f.Schema.MaxItems)
+	}
+	if validators == "" {
+		return ""
+	}
+	return fmt.Sprintf("[]validator.%s{\n%s}", ft.Name, validators)
+}
+
+// frameworkAttrType returns code of the attr.Type, e.g. types.ListType{ElemType: types.StringType}
+func (f *field) frameworkAttrType() (string, error) {
+	ft, err := f.frameworkType()
+	if err != nil {
+		return "", err
+	}
+
+	switch e := f.Elem.(type) {
+	case *field:
+		elem, err := e.frameworkAttrType()
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("types.%sType{ElemType: %s}", ft.Name, elem), nil
+	case block:
+		attrTypes, err := e.frameworkAttrTypesCode()
+		if err != nil {
+			return "", err
+		}
+		if f.GoType != nil {
+			attrTypes = backends.ModelName(f.GoType) + "{}.AttrTypes()"
+		}
+		return fmt.Sprintf("types.%sType{ElemType: types.ObjectType{AttrTypes: %s}}", ft.Name, attrTypes), nil
+	}
+	if f.Schema.Type == schema.TypeMap {
+		return "types.MapType{ElemType: types.StringType}", nil
+	}
+	return ft.AttrType, nil
+}
+
+func (b block) frameworkAttrTypesCode() (string, error) {
+	code := "map[string]attr.Type{\n"
+	for _, name := range b.sortedNames() {
+		attrType, err := b[name].frameworkAttrType()
+		if err != nil {
+			return "", err
+		}
+		code += fmt.Sprintf("%q: %s,\n", name, attrType)
+	}
+	return code + "}", nil
+}
+
+// frameworkObjectCode returns code of schema.NestedBlockObject or schema.NestedAttributeObject
+// of a computed attribute (which cannot contain blocks and all its fields are computed)
+func (b block) frameworkObjectCode(asAttributes bool) (string, error) {
+	attributes, blocks := "", ""
+	for _, name := range b.sortedNames() {
+		f := b[name]
+		content, err := f.frameworkCode(asAttributes)
+		if err != nil {
+			return "", err
+		}
+		if !asAttributes && f.isFrameworkBlock() {
+			blocks += fmt.Sprintf("%q: %s,\n", name, content)
+		} else {
+			attributes += fmt.Sprintf("%q: %s,\n", name, content)
+		}
+	}
+
+	code := "schema.NestedBlockObject{\n"
+	if asAttributes {
+		code = "schema.NestedAttributeObject{\n"
+	}
+	if attributes != "" {
+		code += "Attributes: map[string]schema.Attribute{\n" + attributes + "},\n"
+	}
+	if blocks != "" {
+		code += "Blocks: map[string]schema.Block{\n" + blocks + "},\n"
+	}
+	return code + "}", nil
+}
+
+// frameworkFieldsCode works like fieldsCode, but returns code of attributes & blocks
+// in two separate maps, as these are separate fields of the framework's schema.Schema
+func (b block) frameworkFieldsCode() (map[string]string, map[string]string) {
+	attributes := make(map[string]string, 0)
+	blocks := make(map[string]string, 0)
+	for name, f := range b {
+		content, err := f.frameworkCode(false)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+		if f.isFrameworkBlock() {
+			blocks[name] = content
+		} else {
+			attributes[name] = content
+		}
+	}
+	return attributes, blocks
+}
+
+// AttributesAndBlocksFromStruct works like FromStruct, but separates attributes
+// from blocks, as needed by the framework backend
+func (g *SchemaGenerator) AttributesAndBlocksFromStruct(iface interface{}) (map[string]string, map[string]string) {
+	return g.rootBlock(iface).frameworkFieldsCode()
+}
+
+// ModelsFromStruct returns code of framework models of the struct & all nested structs
+// (struct types with tfsdk tags and AttrTypes method), by model name, e.g. PodSpecModel
+func (g *SchemaGenerator) ModelsFromStruct(iface interface{}) map[string]string {
+	models := make(map[string]string, 0)
+	g.rootBlock(iface).collectModels(reflect.TypeOf(iface), models)
+	return models
+}
+
+func (b block) collectModels(t reflect.Type, models map[string]string) {
+	name := backends.ModelName(t)
+	if _, ok := models[name]; ok {
+		return
+	}
+	content, err := b.modelCode(name)
+	if err != nil {
+		log.Printf("ERROR: %s", err)
+		return
+	}
+	models[name] = content
+
+	for _, fieldName := range b.sortedNames() {
+		f := b[fieldName]
+		if nested, ok := f.Elem.(block); ok && f.GoType != nil {
+			nested.collectModels(f.GoType, models)
+		}
+	}
+}
+
+func (b block) modelCode(name string) (string, error) {
+	data := struct {
+		Name   string
+		Fields []*modelFieldData
+	}{
+		Name:   name,
+		Fields: make([]*modelFieldData, 0),
+	}
+	for _, fieldName := range b.sortedNames() {
+		f := b[fieldName]
+		ft, err := f.frameworkType()
+		if err != nil {
+			return "", err
+		}
+		attrType, err := f.frameworkAttrType()
+		if err != nil {
+			return "", err
+		}
+		data.Fields = append(data.Fields, &modelFieldData{
+			GoName:    f.GoName,
+			ModelType: ft.ModelType,
+			Name:      fieldName,
+			AttrType:  attrType,
+		})
+	}
+
+	buf := bytes.NewBuffer([]byte{})
+	err := modelTemplate.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+type frameworkFieldData struct {
+	Kind          string
+	Description   string
+	Required      bool
+	Optional      bool
+	Computed      bool
+	Sensitive     bool
+	ElementType   string
+	NestedObject  string
+	Default       string
+	Validators    string
+	PlanModifiers string
+}
+
+type modelFieldData struct {
+	GoName    string
+	ModelType string
+	Name      string
+	AttrType  string
+}
+
+var frameworkFieldTemplate = template.Must(template.New("framework-field").Parse(`schema.{{.Kind}}{{"{"}}{{if ne .Description ""}}
+Description: {{printf "%q" .Description}},{{end}}{{if .Required}}
+Required: true,{{end}}{{if .Optional}}
+Optional: true,{{end}}{{if .Computed}}
+Computed: true,{{end}}{{if .Sensitive}}
+Sensitive: true,{{end}}{{if ne .ElementType ""}}
+ElementType: {{.ElementType}},{{end}}{{if ne .NestedObject ""}}
+NestedObject: {{.NestedObject}},{{end}}{{if ne .Default ""}}
+Default: {{.Default}},{{end}}{{if ne .Validators ""}}
+Validators: {{.Validators}},{{end}}{{if ne .PlanModifiers ""}}
+PlanModifiers: {{.PlanModifiers}},{{end}}
+{{"}"}}`))
+
+var modelTemplate = template.Must(template.New("model").Parse(`type {{.Name}} struct {
+{{range .Fields}}{{.GoName}} {{.ModelType}} ` + "`" + `tfsdk:"{{.Name}}"` + "`" + `
+{{end}}}
+
+func (m {{.Name}}) AttrTypes() map[string]attr.Type {
+return map[string]attr.Type{
+{{range .Fields}}{{printf "%q" .Name}}: {{.AttrType}},
+{{end}}}
+}`))
@@ -0,0 +1,165 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+type frameworkPort struct {
+	Number   int32
+	Protocol string `terraform-gen:"default=TCP"`
+}
+
+type frameworkStatus struct {
+	Phase string
+}
+
+type frameworkService struct {
+	Name     string
+	Replicas *int32
+	Tags     []string
+	Labels   map[string]string
+	Port     *frameworkPort
+	Ports    []frameworkPort
+	Status   frameworkStatus
+}
+
+func frameworkGenerator() *SchemaGenerator {
+	return &SchemaGenerator{
+		DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+			if sf.Name == "Name" {
+				return "Name of the service"
+			}
+			return ""
+		},
+		FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			switch sf.Name {
+			case "Name":
+				s.Required = true
+				s.ForceNew = true
+			case "Status":
+				s.Computed = true
+			default:
+				s.Optional = true
+			}
+			return k, true
+		},
+		Backend: backends.Framework,
+	}
+}
+
+func TestAttributesAndBlocksFromStruct(t *testing.T) {
+	g := frameworkGenerator()
+	attributes, blocks := g.AttributesAndBlocksFromStruct(&frameworkService{})
+
+	expectedAttributes := map[string]string{
+		"name": `schema.StringAttribute{
+Description: "Name of the service",
+Required: true,
+PlanModifiers: []planmodifier.String{
+stringplanmodifier.RequiresReplace(),
+},
+}`,
+		"replicas": "schema.Int64Attribute{\nOptional: true,\n}",
+		"tags":     "schema.SetAttribute{\nOptional: true,\nElementType: types.StringType,\n}",
+		"labels":   "schema.MapAttribute{\nOptional: true,\nElementType: types.StringType,\n}",
+		"status": `schema.ListNestedAttribute{
+Computed: true,
+NestedObject: schema.NestedAttributeObject{
+Attributes: map[string]schema.Attribute{
+"phase": schema.StringAttribute{
+Computed: true,
+},
+},
+},
+}`,
+	}
+	if !reflect.DeepEqual(attributes, expectedAttributes) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedAttributes, attributes)
+	}
+
+	portObject := `schema.NestedBlockObject{
+Attributes: map[string]schema.Attribute{
+"number": schema.Int64Attribute{
+Optional: true,
+},
+"protocol": schema.StringAttribute{
+Optional: true,
+Computed: true,
+Default: stringdefault.StaticString("TCP"),
+},
+},
+}`
+	expectedBlocks := map[string]string{
+		"port": `schema.ListNestedBlock{
+NestedObject: ` + portObject + `,
+Validators: []validator.List{
+listvalidator.SizeAtMost(1),
+},
+}`,
+		"ports": "schema.SetNestedBlock{\nNestedObject: " + portObject + ",\n}",
+	}
+	if !reflect.DeepEqual(blocks, expectedBlocks) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedBlocks, blocks)
+	}
+
+	fields := g.FromStruct(&frameworkService{})
+	if len(fields) != len(expectedAttributes)+len(expectedBlocks) {
+		t.Fatalf("Expected all attributes & blocks, given: %s", fields)
+	}
+}
+
+func TestModelsFromStruct(t *testing.T) {
+	g := frameworkGenerator()
+	models := g.ModelsFromStruct(&frameworkService{})
+
+	expectedModels := map[string]string{
+		"frameworkServiceModel": "type frameworkServiceModel struct {\n" +
+			"Labels types.Map `tfsdk:\"labels\"`\n" +
+			"Name types.String `tfsdk:\"name\"`\n" +
+			"Port types.List `tfsdk:\"port\"`\n" +
+			"Ports types.Set `tfsdk:\"ports\"`\n" +
+			"Replicas types.Int64 `tfsdk:\"replicas\"`\n" +
+			"Status types.List `tfsdk:\"status\"`\n" +
+			"Tags types.Set `tfsdk:\"tags\"`\n" +
+			`}
+
+func (m frameworkServiceModel) AttrTypes() map[string]attr.Type {
+return map[string]attr.Type{
+"labels": types.MapType{ElemType: types.StringType},
+"name": types.StringType,
+"port": types.ListType{ElemType: types.ObjectType{AttrTypes: frameworkPortModel{}.AttrTypes()}},
+"ports": types.SetType{ElemType: types.ObjectType{AttrTypes: frameworkPortModel{}.AttrTypes()}},
+"replicas": types.Int64Type,
+"status": types.ListType{ElemType: types.ObjectType{AttrTypes: frameworkStatusModel{}.AttrTypes()}},
+"tags": types.SetType{ElemType: types.StringType},
+}
+}`,
+		"frameworkPortModel": "type frameworkPortModel struct {\n" +
+			"Number types.Int64 `tfsdk:\"number\"`\n" +
+			"Protocol types.String `tfsdk:\"protocol\"`\n" +
+			`}
+
+func (m frameworkPortModel) AttrTypes() map[string]attr.Type {
+return map[string]attr.Type{
+"number": types.Int64Type,
+"protocol": types.StringType,
+}
+}`,
+		"frameworkStatusModel": "type frameworkStatusModel struct {\n" +
+			"Phase types.String `tfsdk:\"phase\"`\n" +
+			`}
+
+func (m frameworkStatusModel) AttrTypes() map[string]attr.Type {
+return map[string]attr.Type{
+"phase": types.StringType,
+}
+}`,
+	}
+	if !reflect.DeepEqual(models, expectedModels) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedModels, models)
+	}
+}
@@ -0,0 +1,22 @@
+package schemagen
+
+import "github.com/hashicorp/terraform/helper/schema"
+
+// Code of functions of fields holding JSON strings (interface{}, map[string]interface{} & json.RawMessage)
+const (
+	// JSONStateFunc normalises JSON, so that formatting & order of keys isn't stored in state
+	JSONStateFunc = `func(v interface{}) string {
+json, _ := structure.NormalizeJsonString(v)
+return json
+}`
+	// JSONDiffSuppressFunc suppresses diffs of semantically equal JSON
+	JSONDiffSuppressFunc = "structure.SuppressJsonDiff"
+)
+
+// setJSON represents the field by JSON string
+func (g *SchemaGenerator) setJSON(f *field) {
+	f.Schema.Type = schema.TypeString
+	f.ValidateFunc = g.backend().ValidateJSONFunc
+	f.StateFunc = JSONStateFunc
+	f.DiffSuppressFunc = JSONDiffSuppressFunc
+}
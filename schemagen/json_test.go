@@ -0,0 +1,54 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+func TestGenerateField_json(t *testing.T) {
+	type SimpleStruct struct {
+		Any    interface{}
+		Object map[string]interface{}
+		Raw    json.RawMessage
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	expectedField := `{
+Type: schema.TypeString,
+Optional: true,
+ValidateFunc: validation.ValidateJsonString,
+StateFunc: func(v interface{}) string {
+json, _ := structure.NormalizeJsonString(v)
+return json
+},
+DiffSuppressFunc: structure.SuppressJsonDiff,
+}`
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"any":    expectedField,
+		"object": expectedField,
+		"raw":    expectedField,
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	g.Backend = backends.PluginSDKv2
+	fields = g.FromStruct(&SimpleStruct{})
+	expectedValidation := "ValidateFunc: validation.StringIsJSON,"
+	if fields["any"] != strings.Replace(expectedField, "ValidateFunc: validation.ValidateJsonString,", expectedValidation, 1) {
+		t.Fatalf("Expected %s, given: %s", expectedValidation, fields["any"])
+	}
+}
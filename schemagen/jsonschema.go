@@ -0,0 +1,98 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+// JSONSchema is a JSON Schema (draft 7+) document
+type JSONSchema struct {
+	root        *specSchema
+	definitions map[string]*specSchema
+}
+
+// LoadJSONSchema reads JSON Schema document from disk
+func LoadJSONSchema(path string) (*JSONSchema, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSONSchema(b)
+}
+
+// ParseJSONSchema parses JSON Schema document incl. its definitions ($defs or definitions)
+func ParseJSONSchema(b []byte) (*JSONSchema, error) {
+	var raw struct {
+		specSchema
+		Defs        map[string]*specSchema `json:"$defs"`
+		Definitions map[string]*specSchema `json:"definitions"`
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse JSON Schema: %s", err)
+	}
+
+	js := &JSONSchema{
+		root:        &raw.specSchema,
+		definitions: make(map[string]*specSchema, len(raw.Defs)+len(raw.Definitions)),
+	}
+	for name, def := range raw.Definitions {
+		js.definitions[name] = def
+	}
+	for name, def := range raw.Defs {
+		js.definitions[name] = def
+	}
+	return js, nil
+}
+
+// JSONSchemaGenerator generates schema from a JSON Schema document
+type JSONSchemaGenerator struct {
+	Schema *JSONSchema
+	// Backend is the plugin SDK targeted by generated code (defaults to backends.HelperSchema)
+	Backend *backends.Backend
+}
+
+// FromDefinition works like SchemaGenerator.FromStruct, but uses the given
+// definition from the document, or the root object if the name is empty
+func (g *JSONSchemaGenerator) FromDefinition(name string) (map[string]string, error) {
+	b, err := g.blockFromDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.fieldsCode(), nil
+}
+
+// SchemaFromDefinition works like FromDefinition, but returns actual schema instead of code
+func (g *JSONSchemaGenerator) SchemaFromDefinition(name string) (map[string]*schema.Schema, error) {
+	b, err := g.blockFromDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.schemaMap(), nil
+}
+
+func (g *JSONSchemaGenerator) blockFromDefinition(name string) (block, error) {
+	def := g.Schema.root
+	refName := "#"
+	if name != "" {
+		var ok bool
+		def, ok = g.Schema.definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("Definition %q not found", name)
+		}
+		refName = name
+	}
+
+	c := &specConverter{
+		definitions:      g.Schema.definitions,
+		refPrefixes:      []string{"#/$defs/", "#/definitions/"},
+		root:             g.Schema.root,
+		uniqueItemsAsSet: true,
+		backend:          backendOrDefault(g.Backend),
+	}
+	return c.blockFromDefinition(refName, def), nil
+}
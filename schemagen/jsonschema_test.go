@@ -0,0 +1,125 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+)
+
+var cowJSONSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "type": "object",
+  "required": ["name"],
+  "properties": {
+    "name": {
+      "type": "string",
+      "description": "Name of the cow",
+      "pattern": "^[a-z]+$",
+      "minLength": 3,
+      "maxLength": 16
+    },
+    "breed": {
+      "type": ["string", "null"],
+      "enum": ["angus", "hereford"]
+    },
+    "nickname": {
+      "type": "string",
+      "minLength": 1
+    },
+    "vaccines": {
+      "type": "array",
+      "uniqueItems": true,
+      "items": {"type": "string"}
+    },
+    "weights": {
+      "type": "array",
+      "items": {"type": "number"}
+    },
+    "labels": {
+      "type": "object",
+      "additionalProperties": {"type": "string"}
+    },
+    "priority": {"type": "integer", "default": 5},
+    "barn": {"$ref": "#/$defs/barn"},
+    "mother": {"$ref": "#"}
+  },
+  "$defs": {
+    "barn": {
+      "type": "object",
+      "properties": {
+        "capacity": {"type": "integer", "minimum": 1}
+      }
+    }
+  }
+}`
+
+func TestJSONSchemaGenerator_FromDefinition(t *testing.T) {
+	js, err := ParseJSONSchema([]byte(cowJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &JSONSchemaGenerator{Schema: js}
+	fields, err := g.FromDefinition("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFields := map[string]string{
+		"name": `{
+Type: schema.TypeString,
+Description: "Name of the cow",
+Required: true,
+ValidateFunc: validation.All(
+validation.StringMatch(regexp.MustCompile(` + "`^[a-z]+$`" + `), ""),
+validation.StringLenBetween(3, 16),
+),
+}`,
+		"breed":    "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.StringInSlice([]string{\"angus\", \"hereford\"}, false),\n}",
+		"nickname": "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.NoZeroValues,\n}",
+		"vaccines": "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\nSet: schema.HashString,\n}",
+		"weights":  "{\nType: schema.TypeList,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeFloat,},\n}",
+		"labels":   "{\nType: schema.TypeMap,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"priority": "{\nType: schema.TypeInt,\nOptional: true,\nDefault: 5,\n}",
+		"barn": `{
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"capacity": {
+Type: schema.TypeInt,
+Optional: true,
+ValidateFunc: validation.IntAtLeast(1),
+},
+},
+},
+}`,
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
+
+func TestJSONSchemaGenerator_definition(t *testing.T) {
+	js, err := ParseJSONSchema([]byte(cowJSONSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &JSONSchemaGenerator{Schema: js}
+	fields, err := g.FromDefinition("barn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		"capacity": "{\nType: schema.TypeInt,\nOptional: true,\nValidateFunc: validation.IntAtLeast(1),\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	_, err = g.FromDefinition("stable")
+	if err == nil {
+		t.Fatal("Expected error for missing definition")
+	}
+}
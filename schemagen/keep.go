@@ -0,0 +1,16 @@
+package schemagen
+
+import (
+	"github.com/radeksimko/terraform-gen/internal/keep"
+)
+
+// PreserveKept returns generated fields with those marked
+// by "terraform-gen:keep" comment in the existing source code
+// taken over from the existing code
+func PreserveKept(fields map[string]string, existingSrc []byte) (map[string]string, error) {
+	kept, err := keep.Fields(existingSrc)
+	if err != nil {
+		return nil, err
+	}
+	return keep.Merge(fields, kept), nil
+}
@@ -0,0 +1,52 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestPreserveKept(t *testing.T) {
+	type SimpleStruct struct {
+		MyInt    int
+		MyString string
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+	existingSrc := `package cattle
+
+var cowSchema = map[string]*schema.Schema{
+	"my_int": {
+		Type: schema.TypeInt,
+	},
+	"my_string": {
+		// terraform-gen:keep
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+	"my_removed_field": {
+		// terraform-gen:keep
+		Type: schema.TypeBool,
+	},
+}
+`
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	fields, err := PreserveKept(g.FromStruct(&SimpleStruct{}), []byte(existingSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		"my_int":           "{\nType: schema.TypeInt,\n}",
+		"my_string":        "{\n\t\t// terraform-gen:keep\n\t\tType:     schema.TypeString,\n\t\tOptional: true,\n\t}",
+		"my_removed_field": "{\n\t\t// terraform-gen:keep\n\t\tType: schema.TypeBool,\n\t}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedFields, fields)
+	}
+}
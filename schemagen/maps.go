@@ -0,0 +1,72 @@
+package schemagen
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// Attributes of blocks representing entries of maps with struct or slice values
+const (
+	MapKeyName   = "key"
+	MapValueName = "value"
+)
+
+// isJSONMap is true for maps represented by a JSON string (terraform-gen:"map=json")
+// instead of list of blocks with key & value (or fields of the struct)
+func isJSONMap(sf *reflect.StructField) bool {
+	return sf != nil && u.TagOptions(sf)["map"] == "json"
+}
+
+// setMapElem represents maps with struct values as list of blocks with key & fields of the struct,
+// maps with slice values as list of blocks with key & value, or either of them as JSON string
+func (g *SchemaGenerator) setMapElem(f *field, mapType reflect.Type, iface interface{}, sf *reflect.StructField) error {
+	if u.DereferencePtrType(mapType).Key().Kind() != reflect.String {
+		return fmt.Errorf("keys of %s are not strings", mapType)
+	}
+	valueType := u.DereferencePtrType(mapType).Elem()
+	valueKind := u.DereferencePtrType(valueType).Kind()
+	if valueKind != reflect.Struct && (valueKind != reflect.Slice || u.IsByteSlice(valueType)) {
+		// byte slices are strings (e.g. data of Kubernetes Secrets)
+		// TODO: Elem(map[string]string)
+		// TODO: Elem(map[string]int)
+		// TODO: Elem(map[string]bool)
+		// TODO: Elem(map[string]float)
+		return nil
+	}
+
+	if isJSONMap(sf) {
+		g.setJSON(f)
+		return nil
+	}
+
+	var entry block
+	if valueKind == reflect.Struct {
+		entry = g.blockFromStructType(valueType)
+		if _, ok := entry[MapKeyName]; ok {
+			return fmt.Errorf("%s has a field conflicting with %q of map entries", valueType, MapKeyName)
+		}
+	} else {
+		elem, err := g.generateElem(valueType.Elem(), iface)
+		if err != nil {
+			return err
+		}
+		entry = block{
+			MapValueName: &field{
+				Schema: &schema.Schema{Type: schema.TypeList, Optional: true},
+				Elem:   elem,
+				GoName: "Value",
+			},
+		}
+	}
+	entry[MapKeyName] = &field{
+		Schema: &schema.Schema{Type: schema.TypeString, Required: true},
+		GoName: "Key",
+	}
+
+	f.Schema.Type = schema.TypeList
+	f.Elem = entry
+	return nil
+}
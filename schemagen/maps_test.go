@@ -0,0 +1,75 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGenerateField_maps(t *testing.T) {
+	type Port struct {
+		Number int
+	}
+	type Conflicting struct {
+		Key string
+	}
+	type SimpleStruct struct {
+		Ports       map[string]Port
+		Aliases     map[string][]string
+		Raw         map[string][]string `terraform-gen:"map=json"`
+		Labels      map[string]string
+		Conflicting map[string]Conflicting
+		ByID        map[int]Port
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"ports": `{
+Type: schema.TypeList,
+Optional: true,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"key": {
+Type: schema.TypeString,
+Required: true,
+},
+"number": {
+Type: schema.TypeInt,
+Optional: true,
+},
+},
+},
+}`,
+		"aliases": `{
+Type: schema.TypeList,
+Optional: true,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"key": {
+Type: schema.TypeString,
+Required: true,
+},
+"value": {
+Type: schema.TypeList,
+Optional: true,
+Elem: &schema.Schema{Type: schema.TypeString,},
+},
+},
+},
+}`,
+		"raw":    "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.ValidateJsonString,\nStateFunc: " + JSONStateFunc + ",\nDiffSuppressFunc: structure.SuppressJsonDiff,\n}",
+		"labels": "{\nType: schema.TypeMap,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
@@ -0,0 +1,375 @@
+package schemagen
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// MigrationGenerator generates scaffolding for migration of state
+// between two versions of the schema, based on changes from DiffSchemas
+type MigrationGenerator struct {
+	// ResourceName is used in names of generated functions, e.g. KubernetesPod
+	ResourceName string
+	// SchemaVersion is the current version of the schema (before the change)
+	SchemaVersion int
+	// Renames maps old paths to new ones, e.g. spec.host_name: spec.hostname.
+	// Unambiguous pairs of removed & added fields of the same type
+	// under the same parent are treated as renames automatically.
+	Renames map[string]string
+}
+
+// Migration holds generated code to be placed in the resource's source & test files
+type Migration struct {
+	// SchemaVersion is the new (incremented) version
+	SchemaVersion int
+	// ResourceFields is to be placed in the schema.Resource declaration
+	ResourceFields string
+	// MigrateStateFunc dispatches migrations for all versions
+	MigrateStateFunc string
+	// StepFunc migrates state from the previous version to SchemaVersion
+	StepFunc string
+	// TestFunc exercises the migration on a synthetic flatmap state
+	TestFunc string
+}
+
+type versionStep struct {
+	From, To int
+}
+
+type migrationStep struct {
+	Comment  string
+	Kind     string
+	Regexp   string
+	NewName  string
+	OldKey   string
+	OldValue string
+	NewKey   string
+}
+
+// FromChanges generates migration of state to the next schema version,
+// with a stub for each removed, renamed or retyped field
+func (mg *MigrationGenerator) FromChanges(changes []*SchemaChange) (*Migration, error) {
+	steps := mg.migrationSteps(changes)
+
+	data := struct {
+		ResourceName  string
+		FromVersion   int
+		SchemaVersion int
+		Versions      []*versionStep
+		Steps         []*migrationStep
+	}{
+		ResourceName:  mg.ResourceName,
+		FromVersion:   mg.SchemaVersion,
+		SchemaVersion: mg.SchemaVersion + 1,
+		Versions:      make([]*versionStep, mg.SchemaVersion+1, mg.SchemaVersion+1),
+		Steps:         steps,
+	}
+	for i := range data.Versions {
+		data.Versions[i] = &versionStep{From: i, To: i + 1}
+	}
+
+	m := &Migration{SchemaVersion: data.SchemaVersion}
+	var err error
+	m.ResourceFields, err = executeTemplate(migrationResourceFieldsTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	m.MigrateStateFunc, err = executeTemplate(migrateStateFuncTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	m.StepFunc, err = executeTemplate(migrationStepFuncTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	m.TestFunc, err = executeTemplate(migrationTestFuncTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (mg *MigrationGenerator) migrationSteps(changes []*SchemaChange) []*migrationStep {
+	renames := make(map[string]string, len(mg.Renames))
+	for from, to := range mg.Renames {
+		renames[from] = to
+	}
+	for from, to := range detectRenames(changes) {
+		if _, ok := renames[from]; !ok {
+			renames[from] = to
+		}
+	}
+	renamedTo := make(map[string]bool, len(renames))
+	for _, to := range renames {
+		renamedTo[to] = true
+	}
+
+	steps := make([]*migrationStep, 0)
+	for _, c := range changes {
+		switch c.Kind {
+		case FieldRemoved:
+			oldKey := flatmapKey(c.Path, c.Old)
+			if to, ok := renames[c.Path]; ok {
+				steps = append(steps, &migrationStep{
+					Comment:  fmt.Sprintf("%s was renamed to %s", c.Path, to),
+					Kind:     "rename",
+					Regexp:   pathRegexp(c.Path),
+					NewName:  lastPathSegment(to),
+					OldKey:   oldKey,
+					OldValue: exampleFlatmapValue(c.Old),
+					NewKey:   renamedFlatmapKey(oldKey, lastPathSegment(to)),
+				})
+				continue
+			}
+			steps = append(steps, &migrationStep{
+				Comment:  fmt.Sprintf("%s was removed", c.Path),
+				Kind:     "remove",
+				Regexp:   pathRegexp(c.Path),
+				OldKey:   oldKey,
+				OldValue: exampleFlatmapValue(c.Old),
+			})
+		case TypeChanged:
+			steps = append(steps, &migrationStep{
+				Comment:  fmt.Sprintf("%s %s", c.Path, c.Message),
+				Kind:     "convert",
+				Regexp:   pathRegexp(c.Path),
+				OldKey:   flatmapKey(c.Path, c.Old),
+				OldValue: exampleFlatmapValue(c.Old),
+			})
+		case FieldAdded:
+			if renamedTo[c.Path] || !c.New.Required {
+				continue
+			}
+			steps = append(steps, &migrationStep{
+				Comment: fmt.Sprintf("%s is a new required field", c.Path),
+				Kind:    "add",
+			})
+		}
+	}
+
+	return steps
+}
+
+// detectRenames pairs removed & added fields of the same type
+// under the same parent, as long as the pair is unambiguous
+func detectRenames(changes []*SchemaChange) map[string]string {
+	removed := make(map[string][]*SchemaChange, 0)
+	added := make(map[string][]*SchemaChange, 0)
+	for _, c := range changes {
+		key := parentPath(c.Path)
+		switch c.Kind {
+		case FieldRemoved:
+			key += "|" + typeDescription(c.Old)
+			removed[key] = append(removed[key], c)
+		case FieldAdded:
+			key += "|" + typeDescription(c.New)
+			added[key] = append(added[key], c)
+		}
+	}
+
+	renames := make(map[string]string, 0)
+	for key, r := range removed {
+		a := added[key]
+		if len(r) == 1 && len(a) == 1 {
+			renames[r[0].Path] = a[0].Path
+		}
+	}
+	return renames
+}
+
+func parentPath(path string) string {
+	idx := strings.LastIndex(path, ".")
+	if idx < 0 {
+		return ""
+	}
+	return path[:idx]
+}
+
+func lastPathSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
+
+// pathRegexp returns regular expression matching all flatmap keys of the field,
+// capturing prefix (incl. list/set indexes) and suffix
+func pathRegexp(path string) string {
+	segments := strings.Split(path, ".")
+	prefix := ""
+	for _, s := range segments[:len(segments)-1] {
+		prefix += regexp.QuoteMeta(s) + `\.[^.]+\.`
+	}
+	return "^(" + prefix + ")" + regexp.QuoteMeta(segments[len(segments)-1]) + `(\..*)?$`
+}
+
+// flatmapKey returns example flatmap key of the field (first item of every list/set)
+func flatmapKey(path string, s *schema.Schema) string {
+	key := strings.Replace(path, ".", ".0.", -1)
+	switch s.Type {
+	case schema.TypeList, schema.TypeSet:
+		return key + ".#"
+	case schema.TypeMap:
+		return key + ".%"
+	}
+	return key
+}
+
+func renamedFlatmapKey(key, newName string) string {
+	suffix := ""
+	if strings.HasSuffix(key, ".#") || strings.HasSuffix(key, ".%") {
+		suffix = key[len(key)-2:]
+		key = key[:len(key)-2]
+	}
+	return parentFlatmapKey(key) + newName + suffix
+}
+
+func parentFlatmapKey(key string) string {
+	return key[:strings.LastIndex(key, ".")+1]
+}
+
+func exampleFlatmapValue(s *schema.Schema) string {
+	switch s.Type {
+	case schema.TypeInt:
+		return "42"
+	case schema.TypeFloat:
+		return "4.2"
+	case schema.TypeBool:
+		return "true"
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		return "0"
+	}
+	return "example"
+}
+
+func executeTemplate(tpl *template.Template, data interface{}) (string, error) {
+	buf := bytes.NewBuffer([]byte{})
+	err := tpl.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+var migrationResourceFieldsTemplate = template.Must(template.New("migration-resource-fields").Parse(`SchemaVersion: {{.SchemaVersion}},
+MigrateState: resource{{.ResourceName}}MigrateState,`))
+
+var migrateStateFuncTemplate = template.Must(template.New("migrate-state").Parse(`func resource{{.ResourceName}}MigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+var err error
+switch v {
+{{- range .Versions}}
+case {{.From}}:
+log.Println("[INFO] Found {{$.ResourceName}} State v{{.From}}; migrating to v{{.To}}")
+is, err = migrate{{$.ResourceName}}StateV{{.From}}toV{{.To}}(is)
+if err != nil {
+return is, err
+}
+{{- if ne .From $.FromVersion}}
+fallthrough
+{{- else}}
+return is, nil
+{{- end}}
+{{- end}}
+default:
+return is, fmt.Errorf("Unexpected schema version: %d", v)
+}
+}`))
+
+var migrationStepFuncTemplate = template.Must(template.New("migration-step").Parse(`func migrate{{.ResourceName}}StateV{{.FromVersion}}toV{{.SchemaVersion}}(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+if is.Empty() {
+log.Println("[DEBUG] Empty InstanceState; nothing to migrate.")
+return is, nil
+}
+
+log.Printf("[DEBUG] Attributes before migration: %#v", is.Attributes)
+{{range $i, $step := .Steps}}
+// {{.Comment}}
+{{- if eq .Kind "add"}}
+// TODO: Set value of the new field (if it cannot be left empty)
+{{- else}}
+re{{$i}} := regexp.MustCompile(` + "`{{.Regexp}}`" + `)
+{{- if eq .Kind "rename"}}
+for k, v := range is.Attributes {
+if m := re{{$i}}.FindStringSubmatch(k); m != nil {
+delete(is.Attributes, k)
+is.Attributes[m[1]+{{printf "%q" .NewName}}+m[2]] = v
+}
+}
+{{- else if eq .Kind "remove"}}
+for k := range is.Attributes {
+if re{{$i}}.MatchString(k) {
+// TODO: Make sure the data can be dropped
+delete(is.Attributes, k)
+}
+}
+{{- else}}
+for k, v := range is.Attributes {
+if re{{$i}}.MatchString(k) {
+// TODO: Convert the value to the new type
+is.Attributes[k] = v
+}
+}
+{{- end}}
+{{- end}}
+{{end}}
+log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
+return is, nil
+}`))
+
+var migrationTestFuncTemplate = template.Must(template.New("migration-test").Parse(`func Test{{.ResourceName}}MigrateState(t *testing.T) {
+cases := map[string]struct {
+StateVersion int
+Attributes   map[string]string
+Expected     map[string]string
+Removed      []string
+Meta         interface{}
+}{
+"v{{.FromVersion}}_{{.SchemaVersion}}": {
+StateVersion: {{.FromVersion}},
+Attributes: map[string]string{
+{{- range .Steps}}{{if ne .OldKey ""}}
+{{printf "%q" .OldKey}}: {{printf "%q" .OldValue}},
+{{- end}}{{end}}
+},
+Expected: map[string]string{
+{{- range .Steps}}{{if eq .Kind "rename"}}
+{{printf "%q" .NewKey}}: {{printf "%q" .OldValue}},
+{{- else if eq .Kind "convert"}}
+// TODO: Expect converted value
+{{printf "%q" .OldKey}}: {{printf "%q" .OldValue}},
+{{- end}}{{end}}
+},
+Removed: []string{
+{{- range .Steps}}{{if or (eq .Kind "rename") (eq .Kind "remove")}}
+{{printf "%q" .OldKey}},
+{{- end}}{{end}}
+},
+},
+}
+
+for tn, tc := range cases {
+is := &terraform.InstanceState{
+ID:         "i-abc123",
+Attributes: tc.Attributes,
+}
+is, err := resource{{.ResourceName}}MigrateState(tc.StateVersion, is, tc.Meta)
+if err != nil {
+t.Fatalf("bad: %s, err: %#v", tn, err)
+}
+
+for k, v := range tc.Expected {
+if is.Attributes[k] != v {
+t.Fatalf("bad: %s\n\n expected: %#v -> %#v\n got: %#v -> %#v\n in: %#v",
+tn, k, v, k, is.Attributes[k], is.Attributes)
+}
+}
+for _, k := range tc.Removed {
+if _, ok := is.Attributes[k]; ok {
+t.Fatalf("bad: %s\n\n expected %#v to be removed\n in: %#v", tn, k, is.Attributes)
+}
+}
+}
+}`))
@@ -0,0 +1,122 @@
+package schemagen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestMigrationGenerator_FromChanges(t *testing.T) {
+	oldSchema := map[string]*schema.Schema{
+		"spec": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"host_name": {Type: schema.TypeString, Optional: true},
+					"ports":     {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeInt}},
+				},
+			},
+		},
+		"legacy": {Type: schema.TypeBool, Optional: true},
+	}
+	newSchema := map[string]*schema.Schema{
+		"spec": {
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"hostname": {Type: schema.TypeString, Optional: true},
+					"ports":    {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+				},
+			},
+		},
+		"zone": {Type: schema.TypeString, Required: true},
+	}
+
+	mg := &MigrationGenerator{ResourceName: "CattleCow", SchemaVersion: 0}
+	m, err := mg.FromChanges(DiffSchemas(oldSchema, newSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.SchemaVersion != 1 {
+		t.Fatalf("Expected SchemaVersion 1, given: %d", m.SchemaVersion)
+	}
+
+	expectedResourceFields := `SchemaVersion: 1,
+MigrateState: resourceCattleCowMigrateState,`
+	if m.ResourceFields != expectedResourceFields {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedResourceFields, m.ResourceFields)
+	}
+
+	expectedMigrateStateFunc := `func resourceCattleCowMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+var err error
+switch v {
+case 0:
+log.Println("[INFO] Found CattleCow State v0; migrating to v1")
+is, err = migrateCattleCowStateV0toV1(is)
+if err != nil {
+return is, err
+}
+return is, nil
+default:
+return is, fmt.Errorf("Unexpected schema version: %d", v)
+}
+}`
+	if m.MigrateStateFunc != expectedMigrateStateFunc {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedMigrateStateFunc, m.MigrateStateFunc)
+	}
+
+	expectedStepFunc := `func migrateCattleCowStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+if is.Empty() {
+log.Println("[DEBUG] Empty InstanceState; nothing to migrate.")
+return is, nil
+}
+
+log.Printf("[DEBUG] Attributes before migration: %#v", is.Attributes)
+
+// legacy was removed
+re0 := regexp.MustCompile(` + "`" + `^()legacy(\..*)?$` + "`" + `)
+for k := range is.Attributes {
+if re0.MatchString(k) {
+// TODO: Make sure the data can be dropped
+delete(is.Attributes, k)
+}
+}
+
+// spec.host_name was renamed to spec.hostname
+re1 := regexp.MustCompile(` + "`" + `^(spec\.[^.]+\.)host_name(\..*)?$` + "`" + `)
+for k, v := range is.Attributes {
+if m := re1.FindStringSubmatch(k); m != nil {
+delete(is.Attributes, k)
+is.Attributes[m[1]+"hostname"+m[2]] = v
+}
+}
+
+// spec.ports type changed from TypeSet(TypeInt) to TypeSet(TypeString)
+re2 := regexp.MustCompile(` + "`" + `^(spec\.[^.]+\.)ports(\..*)?$` + "`" + `)
+for k, v := range is.Attributes {
+if re2.MatchString(k) {
+// TODO: Convert the value to the new type
+is.Attributes[k] = v
+}
+}
+
+// zone is a new required field
+// TODO: Set value of the new field (if it cannot be left empty)
+
+log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
+return is, nil
+}`
+	if m.StepFunc != expectedStepFunc {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedStepFunc, m.StepFunc)
+	}
+
+	if !strings.Contains(m.TestFunc, "func TestCattleCowMigrateState(t *testing.T) {") {
+		t.Fatalf("Unexpected test function: %s", m.TestFunc)
+	}
+	if !strings.Contains(m.TestFunc, `"spec.0.hostname": "example",`) {
+		t.Fatalf("Expected renamed attribute in test function: %s", m.TestFunc)
+	}
+}
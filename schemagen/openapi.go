@@ -0,0 +1,87 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+// OpenAPISpec holds definitions of an OpenAPI 2 (Swagger) or OpenAPI 3 specification
+type OpenAPISpec struct {
+	definitions map[string]*specSchema
+}
+
+// LoadOpenAPISpec reads OpenAPI specification (in JSON) from disk
+func LoadOpenAPISpec(path string) (*OpenAPISpec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOpenAPISpec(b)
+}
+
+// ParseOpenAPISpec parses OpenAPI 2 (definitions) or OpenAPI 3 (components.schemas) specification
+func ParseOpenAPISpec(b []byte) (*OpenAPISpec, error) {
+	var raw struct {
+		Definitions map[string]*specSchema `json:"definitions"`
+		Components  struct {
+			Schemas map[string]*specSchema `json:"schemas"`
+		} `json:"components"`
+	}
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse OpenAPI spec: %s", err)
+	}
+
+	spec := &OpenAPISpec{definitions: raw.Definitions}
+	if spec.definitions == nil {
+		spec.definitions = raw.Components.Schemas
+	}
+	if spec.definitions == nil {
+		return nil, fmt.Errorf("No definitions found in OpenAPI spec")
+	}
+	return spec, nil
+}
+
+// OpenAPIGenerator generates schema from definitions of an OpenAPI specification
+type OpenAPIGenerator struct {
+	Spec *OpenAPISpec
+	// Backend is the plugin SDK targeted by generated code (defaults to backends.HelperSchema)
+	Backend *backends.Backend
+}
+
+// FromDefinition works like SchemaGenerator.FromStruct, but uses the given
+// definition (e.g. io.k8s.api.core.v1.PodSpec) from the spec instead of a struct
+func (g *OpenAPIGenerator) FromDefinition(name string) (map[string]string, error) {
+	b, err := g.blockFromDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.fieldsCode(), nil
+}
+
+// SchemaFromDefinition works like FromDefinition, but returns actual schema instead of code
+func (g *OpenAPIGenerator) SchemaFromDefinition(name string) (map[string]*schema.Schema, error) {
+	b, err := g.blockFromDefinition(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.schemaMap(), nil
+}
+
+func (g *OpenAPIGenerator) blockFromDefinition(name string) (block, error) {
+	def, ok := g.Spec.definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("Definition %q not found", name)
+	}
+	c := &specConverter{
+		definitions: g.Spec.definitions,
+		refPrefixes: []string{"#/definitions/", "#/components/schemas/"},
+		backend:     backendOrDefault(g.Backend),
+	}
+	return c.blockFromDefinition(name, def), nil
+}
@@ -0,0 +1,195 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+var swaggerSpec = `{
+  "swagger": "2.0",
+  "definitions": {
+    "cattle.Cow": {
+      "description": "Cow is a bovine animal.",
+      "required": ["name", "breed"],
+      "properties": {
+        "name": {
+          "description": "Name of the cow",
+          "type": "string"
+        },
+        "breed": {
+          "type": "string",
+          "enum": ["angus", "hereford"]
+        },
+        "age": {
+          "type": "integer",
+          "format": "int32",
+          "minimum": 0,
+          "maximum": 30
+        },
+        "weight": {
+          "type": "number",
+          "minimum": 0.5
+        },
+        "bornAt": {
+          "type": "string",
+          "format": "date-time",
+          "readOnly": true
+        },
+        "isMilking": {
+          "type": "boolean"
+        },
+        "tags": {
+          "type": "array",
+          "items": {"type": "string"}
+        },
+        "labels": {
+          "type": "object",
+          "additionalProperties": {"type": "string"}
+        },
+        "owner": {
+          "description": "Owner of the cow",
+          "$ref": "#/definitions/cattle.Owner"
+        },
+        "calves": {
+          "type": "array",
+          "items": {"$ref": "#/definitions/cattle.Cow"}
+        }
+      }
+    },
+    "cattle.Owner": {
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string"},
+        "uid": {"type": "string", "readOnly": true}
+      }
+    }
+  }
+}`
+
+func TestOpenAPIGenerator_FromDefinition(t *testing.T) {
+	spec, err := ParseOpenAPISpec([]byte(swaggerSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &OpenAPIGenerator{Spec: spec}
+	fields, err := g.FromDefinition("cattle.Cow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFields := map[string]string{
+		"name":       "{\nType: schema.TypeString,\nDescription: \"Name of the cow\",\nRequired: true,\n}",
+		"breed":      "{\nType: schema.TypeString,\nRequired: true,\nValidateFunc: validation.StringInSlice([]string{\"angus\", \"hereford\"}, false),\n}",
+		"age":        "{\nType: schema.TypeInt,\nOptional: true,\nValidateFunc: validation.IntBetween(0, 30),\n}",
+		"weight":     "{\nType: schema.TypeFloat,\nOptional: true,\nValidateFunc: validation.FloatBetween(0.5, math.MaxFloat64),\n}",
+		"born_at":    "{\nType: schema.TypeString,\nComputed: true,\nValidateFunc: validation.ValidateRFC3339TimeString,\n}",
+		"is_milking": "{\nType: schema.TypeBool,\nOptional: true,\n}",
+		"tags":       "{\nType: schema.TypeSet,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\nSet: schema.HashString,\n}",
+		"labels":     "{\nType: schema.TypeMap,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"owner": `{
+Type: schema.TypeList,
+Description: "Owner of the cow",
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"name": {
+Type: schema.TypeString,
+Required: true,
+},
+"uid": {
+Type: schema.TypeString,
+Computed: true,
+},
+},
+},
+}`,
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
+
+func TestOpenAPIGenerator_openAPI3(t *testing.T) {
+	spec, err := ParseOpenAPISpec([]byte(`{
+  "openapi": "3.0.0",
+  "components": {
+    "schemas": {
+      "Barn": {
+        "type": "object",
+        "properties": {
+          "address": {
+            "description": "Address of the barn",
+            "allOf": [{"$ref": "#/components/schemas/Address"}]
+          },
+          "capacity": {"type": "integer", "enum": [10, 20]}
+        }
+      },
+      "Address": {
+        "type": "object",
+        "properties": {
+          "city": {"type": "string"}
+        }
+      }
+    }
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &OpenAPIGenerator{Spec: spec}
+	s, err := g.SchemaFromDefinition("Barn")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedSchema := map[string]*schema.Schema{
+		"address": {
+			Type:        schema.TypeList,
+			Description: "Address of the barn",
+			Optional:    true,
+			MaxItems:    1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"city": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"capacity": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+	}
+	if !reflect.DeepEqual(s, expectedSchema) {
+		t.Fatalf("Expected: %#v\n\nGiven: %#v\n", expectedSchema, s)
+	}
+
+	fields, err := g.FromDefinition("Barn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCapacity := "{\nType: schema.TypeInt,\nOptional: true,\nValidateFunc: validation.IntInSlice([]int{10, 20}),\n}"
+	if fields["capacity"] != expectedCapacity {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedCapacity, fields["capacity"])
+	}
+}
+
+func TestOpenAPIGenerator_missingDefinition(t *testing.T) {
+	spec, err := ParseOpenAPISpec([]byte(swaggerSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &OpenAPIGenerator{Spec: spec}
+	_, err = g.FromDefinition("cattle.Horse")
+	if err == nil {
+		t.Fatal("Expected error for missing definition")
+	}
+}
@@ -0,0 +1,300 @@
+package schemagen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	"github.com/radeksimko/terraform-gen/converters"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ProtoDescriptors holds messages & enums of a FileDescriptorSet compiled by protoc, e.g.
+// protoc --include_source_info --descriptor_set_out=cattle.pb cattle.proto
+type ProtoDescriptors struct {
+	// messages & enums are keyed by full name, e.g. cattle.Cow
+	messages map[string]*descriptorpb.DescriptorProto
+	enums    map[string]*descriptorpb.EnumDescriptorProto
+	// comments holds leading comments of fields (if source info was included), e.g. cattle.Cow.name
+	comments map[string]string
+}
+
+// LoadFileDescriptorSet reads FileDescriptorSet from disk
+func LoadFileDescriptorSet(path string) (*ProtoDescriptors, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFileDescriptorSet(b)
+}
+
+// ParseFileDescriptorSet parses binary FileDescriptorSet
+func ParseFileDescriptorSet(b []byte) (*ProtoDescriptors, error) {
+	fds := &descriptorpb.FileDescriptorSet{}
+	err := proto.Unmarshal(b, fds)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to parse FileDescriptorSet: %s", err)
+	}
+
+	d := &ProtoDescriptors{
+		messages: make(map[string]*descriptorpb.DescriptorProto, 0),
+		enums:    make(map[string]*descriptorpb.EnumDescriptorProto, 0),
+		comments: make(map[string]string, 0),
+	}
+	for _, fd := range fds.File {
+		comments := make(map[string]string, 0)
+		for _, loc := range fd.GetSourceCodeInfo().GetLocation() {
+			if c := strings.TrimSpace(loc.GetLeadingComments()); c != "" {
+				comments[protoPathKey(loc.Path)] = c
+			}
+		}
+
+		for i, m := range fd.MessageType {
+			d.addMessage(fd.GetPackage(), m, []int32{4, int32(i)}, comments)
+		}
+		for _, e := range fd.EnumType {
+			d.enums[protoFullName(fd.GetPackage(), e.GetName())] = e
+		}
+	}
+
+	return d, nil
+}
+
+func (d *ProtoDescriptors) addMessage(prefix string, m *descriptorpb.DescriptorProto, path []int32, comments map[string]string) {
+	name := protoFullName(prefix, m.GetName())
+	d.messages[name] = m
+
+	for i, f := range m.Field {
+		if c, ok := comments[protoPathKey(append(path[:len(path):len(path)], 2, int32(i)))]; ok {
+			d.comments[name+"."+f.GetName()] = c
+		}
+	}
+	for i, nested := range m.NestedType {
+		d.addMessage(name, nested, append(path[:len(path):len(path)], 3, int32(i)), comments)
+	}
+	for _, e := range m.EnumType {
+		d.enums[protoFullName(name, e.GetName())] = e
+	}
+}
+
+func protoFullName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// protoPathKey turns path of SourceCodeInfo location into a map key
+func protoPathKey(path []int32) string {
+	parts := make([]string, len(path), len(path))
+	for i, p := range path {
+		parts[i] = strconv.Itoa(int(p))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ProtobufGenerator generates schema from messages of a FileDescriptorSet
+type ProtobufGenerator struct {
+	Descriptors *ProtoDescriptors
+	// Backend is the plugin SDK targeted by generated code (defaults to backends.HelperSchema)
+	Backend *backends.Backend
+
+	// resolving holds messages being converted, to detect recursive messages
+	resolving map[string]bool
+}
+
+// FromMessage works like SchemaGenerator.FromStruct,
+// but uses the given message (e.g. cattle.Cow) instead of a struct
+func (g *ProtobufGenerator) FromMessage(name string) (map[string]string, error) {
+	b, err := g.blockFromMessage(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.fieldsCode(), nil
+}
+
+// SchemaFromMessage works like FromMessage, but returns actual schema instead of code
+func (g *ProtobufGenerator) SchemaFromMessage(name string) (map[string]*schema.Schema, error) {
+	b, err := g.blockFromMessage(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.schemaMap(), nil
+}
+
+func (g *ProtobufGenerator) blockFromMessage(name string) (block, error) {
+	m, ok := g.Descriptors.messages[name]
+	if !ok {
+		return nil, fmt.Errorf("Message %q not found", name)
+	}
+	g.resolving = map[string]bool{name: true}
+	return g.blockFromDescriptor(name, m), nil
+}
+
+func (g *ProtobufGenerator) blockFromDescriptor(msgName string, m *descriptorpb.DescriptorProto) block {
+	fields := make(block, 0)
+	for _, fd := range m.Field {
+		if isOneofMember(fd) {
+			continue
+		}
+		f, err := g.generateField(fd)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+
+		if fd.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+			f.Schema.Required = true
+		} else {
+			f.Schema.Optional = true
+		}
+		f.Schema.Description = g.Descriptors.comments[msgName+"."+fd.GetName()]
+		f.Schema.Sensitive = !isListOrSet(f.Schema) && isSensitiveName(fd.GetName(), DefaultSensitivePatterns)
+		fields[fd.GetName()] = f
+	}
+	return fields
+}
+
+func (g *ProtobufGenerator) generateField(fd *descriptorpb.FieldDescriptorProto) (*field, error) {
+	if fd.GetLabel() != descriptorpb.FieldDescriptorProto_LABEL_REPEATED {
+		return g.generateSingleField(fd)
+	}
+
+	if entry, ok := g.mapEntry(fd); ok {
+		f := &field{Schema: &schema.Schema{Type: schema.TypeMap}}
+		for _, vfd := range entry.Field {
+			if vfd.GetName() != "value" || vfd.GetType() == descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+				continue
+			}
+			elem, err := g.generateSingleField(vfd)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to generate Elem for %q: %s", fd.GetName(), err)
+			}
+			f.Elem = elem
+		}
+		return f, nil
+	}
+
+	elem, err := g.generateSingleField(fd)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to generate Elem for %q: %s", fd.GetName(), err)
+	}
+	f := &field{Schema: &schema.Schema{Type: schema.TypeList}}
+	if elem.Schema.Type == schema.TypeList && elem.Schema.MaxItems == 1 {
+		f.Elem = elem.Elem
+	} else {
+		f.Elem = elem
+	}
+	return f, nil
+}
+
+// generateSingleField generates field ignoring the repeated label
+func (g *ProtobufGenerator) generateSingleField(fd *descriptorpb.FieldDescriptorProto) (*field, error) {
+	f := &field{Schema: &schema.Schema{}}
+
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_UINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_SINT32, descriptorpb.FieldDescriptorProto_TYPE_SINT64,
+		descriptorpb.FieldDescriptorProto_TYPE_FIXED32, descriptorpb.FieldDescriptorProto_TYPE_FIXED64,
+		descriptorpb.FieldDescriptorProto_TYPE_SFIXED32, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		f.Schema.Type = schema.TypeInt
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		f.Schema.Type = schema.TypeFloat
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		f.Schema.Type = schema.TypeBool
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING, descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		f.Schema.Type = schema.TypeString
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		enumName := strings.TrimPrefix(fd.GetTypeName(), ".")
+		e, ok := g.Descriptors.enums[enumName]
+		if !ok {
+			return nil, fmt.Errorf("Enum %q not found", enumName)
+		}
+		values := make([]string, len(e.Value), len(e.Value))
+		for i, v := range e.Value {
+			values[i] = strconv.Quote(v.GetName())
+		}
+		f.Schema.Type = schema.TypeString
+		f.ValidateFunc = fmt.Sprintf("validation.StringInSlice([]string{%s}, false)", strings.Join(values, ", "))
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE:
+		msgName := strings.TrimPrefix(fd.GetTypeName(), ".")
+		if wkt, ok := protoWellKnownTypes[msgName]; ok {
+			f.Schema.Type = wkt.Type
+			f.ValidateFunc = wkt.ValidateFunc
+			if wkt.ValidateRFC3339 {
+				f.ValidateFunc = backendOrDefault(g.Backend).ValidateRFC3339Func
+			}
+			break
+		}
+		m, ok := g.Descriptors.messages[msgName]
+		if !ok {
+			return nil, fmt.Errorf("Message %q not found", msgName)
+		}
+		if g.resolving[msgName] {
+			return nil, fmt.Errorf("Skipping %q (recursive message %q)", fd.GetName(), msgName)
+		}
+		g.resolving[msgName] = true
+		defer delete(g.resolving, msgName)
+
+		f.Schema.Type = schema.TypeList
+		f.Schema.MaxItems = 1
+		f.Elem = g.blockFromDescriptor(msgName, m)
+	default:
+		return nil, fmt.Errorf("Unable to process: %s (%s)", fd.GetName(), fd.GetType())
+	}
+
+	return f, nil
+}
+
+// isOneofMember is true for members of real oneofs, which are skipped
+// like their Go interface fields are by helpergen.ProtobufFieldFilter
+// (proto3 optional fields are in synthetic oneofs, but kept)
+func isOneofMember(fd *descriptorpb.FieldDescriptorProto) bool {
+	return fd.OneofIndex != nil && !fd.GetProto3Optional()
+}
+
+// mapEntry returns the synthetic entry message if the field is a map<>
+func (g *ProtobufGenerator) mapEntry(fd *descriptorpb.FieldDescriptorProto) (*descriptorpb.DescriptorProto, bool) {
+	if fd.GetType() != descriptorpb.FieldDescriptorProto_TYPE_MESSAGE {
+		return nil, false
+	}
+	m, ok := g.Descriptors.messages[strings.TrimPrefix(fd.GetTypeName(), ".")]
+	if !ok || !m.GetOptions().GetMapEntry() {
+		return nil, false
+	}
+	return m, true
+}
+
+type protoWellKnownType struct {
+	Type         schema.ValueType
+	ValidateFunc string
+	// ValidateRFC3339 validates values by ValidateRFC3339Func of the backend instead
+	ValidateRFC3339 bool
+}
+
+// protoWellKnownTypes are represented as primitive types instead of nested blocks
+var protoWellKnownTypes = map[string]protoWellKnownType{
+	"google.protobuf.Duration": {
+		Type:         schema.TypeString,
+		ValidateFunc: converters.DurationValidateFunc,
+	},
+	"google.protobuf.Timestamp": {
+		Type:            schema.TypeString,
+		ValidateRFC3339: true,
+	},
+	"google.protobuf.StringValue": {Type: schema.TypeString},
+	"google.protobuf.BytesValue":  {Type: schema.TypeString},
+	"google.protobuf.BoolValue":   {Type: schema.TypeBool},
+	"google.protobuf.Int32Value":  {Type: schema.TypeInt},
+	"google.protobuf.Int64Value":  {Type: schema.TypeInt},
+	"google.protobuf.UInt32Value": {Type: schema.TypeInt},
+	"google.protobuf.UInt64Value": {Type: schema.TypeInt},
+	"google.protobuf.FloatValue":  {Type: schema.TypeFloat},
+	"google.protobuf.DoubleValue": {Type: schema.TypeFloat},
+}
@@ -0,0 +1,208 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/backends"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func protoField(name string, number int32, label descriptorpb.FieldDescriptorProto_Label,
+	typ descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	fd := &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(number),
+		Label:  label.Enum(),
+		Type:   typ.Enum(),
+	}
+	if typeName != "" {
+		fd.TypeName = proto.String(typeName)
+	}
+	return fd
+}
+
+func oneofMember(fd *descriptorpb.FieldDescriptorProto, index int32) *descriptorpb.FieldDescriptorProto {
+	fd.OneofIndex = proto.Int32(index)
+	return fd
+}
+
+func proto3Optional(fd *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	fd.Proto3Optional = proto.Bool(true)
+	return fd
+}
+
+// cattleFileDescriptorSet is equivalent of protoc output for:
+//
+//	syntax = "proto3";
+//	package cattle;
+//
+//	message Cow {
+//	  enum Breed { ANGUS = 0; HEREFORD = 1; }
+//	  // Name of the cow
+//	  string name = 1;
+//	  Breed breed = 2;
+//	  repeated string tags = 3;
+//	  map<string, string> labels = 4;
+//	  google.protobuf.Duration milking_interval = 5;
+//	  Owner owner = 6;
+//	  repeated Cow calves = 7;
+//	  repeated Owner previous_owners = 8;
+//	  google.protobuf.StringValue nickname = 9;
+//	  oneof origin {
+//	    string farm = 10;
+//	    string market = 11;
+//	  }
+//	  optional int32 weight = 12;
+//	  google.protobuf.Timestamp born_at = 13;
+//	}
+//	message Owner {
+//	  string name = 1;
+//	  int64 age = 2;
+//	}
+func cattleFileDescriptorSet() *descriptorpb.FileDescriptorSet {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED
+
+	return &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("cattle.proto"),
+				Package: proto.String("cattle"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Cow"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							protoField("name", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							protoField("breed", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".cattle.Cow.Breed"),
+							protoField("tags", 3, repeated, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							protoField("labels", 4, repeated, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".cattle.Cow.LabelsEntry"),
+							protoField("milking_interval", 5, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Duration"),
+							protoField("owner", 6, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".cattle.Owner"),
+							protoField("calves", 7, repeated, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".cattle.Cow"),
+							protoField("previous_owners", 8, repeated, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".cattle.Owner"),
+							protoField("nickname", 9, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.StringValue"),
+							oneofMember(protoField("farm", 10, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""), 0),
+							oneofMember(protoField("market", 11, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""), 0),
+							proto3Optional(oneofMember(protoField("weight", 12, optional, descriptorpb.FieldDescriptorProto_TYPE_INT32, ""), 1)),
+							protoField("born_at", 13, optional, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+						},
+						OneofDecl: []*descriptorpb.OneofDescriptorProto{
+							{Name: proto.String("origin")},
+							{Name: proto.String("_weight")},
+						},
+						NestedType: []*descriptorpb.DescriptorProto{
+							{
+								Name: proto.String("LabelsEntry"),
+								Field: []*descriptorpb.FieldDescriptorProto{
+									protoField("key", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+									protoField("value", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+								},
+								Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+							},
+						},
+						EnumType: []*descriptorpb.EnumDescriptorProto{
+							{
+								Name: proto.String("Breed"),
+								Value: []*descriptorpb.EnumValueDescriptorProto{
+									{Name: proto.String("ANGUS"), Number: proto.Int32(0)},
+									{Name: proto.String("HEREFORD"), Number: proto.Int32(1)},
+								},
+							},
+						},
+					},
+					{
+						Name: proto.String("Owner"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							protoField("name", 1, optional, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+							protoField("age", 2, optional, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+						},
+					},
+				},
+				SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+					Location: []*descriptorpb.SourceCodeInfo_Location{
+						{
+							Path:            []int32{4, 0, 2, 0},
+							LeadingComments: proto.String(" Name of the cow\n"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestProtobufGenerator_FromMessage(t *testing.T) {
+	b, err := proto.Marshal(cattleFileDescriptorSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := ParseFileDescriptorSet(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &ProtobufGenerator{Descriptors: d}
+	fields, err := g.FromMessage("cattle.Cow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownerElem := `&schema.Resource{
+Schema: map[string]*schema.Schema{
+"age": {
+Type: schema.TypeInt,
+Optional: true,
+},
+"name": {
+Type: schema.TypeString,
+Optional: true,
+},
+},
+}`
+	expectedFields := map[string]string{
+		"name":   "{\nType: schema.TypeString,\nDescription: \"Name of the cow\",\nOptional: true,\n}",
+		"breed":  "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.StringInSlice([]string{\"ANGUS\", \"HEREFORD\"}, false),\n}",
+		"tags":   "{\nType: schema.TypeList,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"labels": "{\nType: schema.TypeMap,\nOptional: true,\nElem: &schema.Schema{Type: schema.TypeString,},\n}",
+		"milking_interval": "{\nType: schema.TypeString,\nOptional: true,\n" +
+			"ValidateFunc: validation.StringMatch(regexp.MustCompile(`^-?([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`), \"must be a duration, e.g. 1h30m\"),\n}",
+		"owner":           "{\nType: schema.TypeList,\nOptional: true,\nMaxItems: 1,\nElem: " + ownerElem + ",\n}",
+		"previous_owners": "{\nType: schema.TypeList,\nOptional: true,\nElem: " + ownerElem + ",\n}",
+		"nickname":        "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"weight":          "{\nType: schema.TypeInt,\nOptional: true,\n}",
+		"born_at":         "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.ValidateRFC3339TimeString,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	g.Backend = backends.PluginSDKv2
+	fields, err = g.FromMessage("cattle.Cow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.IsRFC3339Time,\n}"
+	if fields["born_at"] != expected {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expected, fields["born_at"])
+	}
+}
+
+func TestProtobufGenerator_missingMessage(t *testing.T) {
+	b, err := proto.Marshal(cattleFileDescriptorSet())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := ParseFileDescriptorSet(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &ProtobufGenerator{Descriptors: d}
+	_, err = g.FromMessage("cattle.Horse")
+	if err == nil {
+		t.Fatal("Expected error for missing message")
+	}
+}
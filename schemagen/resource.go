@@ -0,0 +1,142 @@
+package schemagen
+
+import (
+	"fmt"
+	"text/template"
+)
+
+// ResourceGenerator generates declaration of a resource with its schema
+// and stubs of CRUD functions, with signatures of the targeted backend
+type ResourceGenerator struct {
+	// ResourceName is used in names of generated functions, e.g. KubernetesPod
+	ResourceName string
+	// Schema generates the schema, its Backend is targeted by the whole resource
+	Schema *SchemaGenerator
+	// Importable adds an importer passing the ID through
+	Importable bool
+}
+
+// Resource holds generated code of a resource
+type Resource struct {
+	// Imports are import paths which may be used by the code
+	Imports []string
+	// ResourceFunc returns the *schema.Resource, e.g. resourceKubernetesPod
+	ResourceFunc string
+	// CRUDFuncs are stubs of functions referenced from ResourceFunc, by name
+	CRUDFuncs map[string]string
+}
+
+type crudFunc struct {
+	Op      string
+	Comment string
+	// Next is the operation called at the end (e.g. Read after Create), if any
+	Next string
+}
+
+// FromStruct generates the resource with schema of the struct.
+// Update is only generated if any field can be updated in-place.
+func (rg *ResourceGenerator) FromStruct(iface interface{}) (*Resource, error) {
+	backend := rg.Schema.backend()
+	if backend.Framework {
+		return nil, fmt.Errorf("Resources are not supported by the %s backend", backend.Name)
+	}
+	b := rg.Schema.rootBlock(iface)
+
+	funcs := []*crudFunc{
+		{Op: "Create", Comment: "Create the resource and set its ID via d.SetId", Next: "Read"},
+		{Op: "Read", Comment: "Read the resource and set its fields via d.Set (or unset the ID if it's gone)"},
+	}
+	if b.isUpdatable() {
+		funcs = append(funcs, &crudFunc{Op: "Update", Comment: "Update fields which have changed (see d.HasChange)", Next: "Read"})
+	}
+	funcs = append(funcs, &crudFunc{Op: "Delete", Comment: "Delete the resource"})
+
+	data := struct {
+		ResourceName string
+		ContextFuncs bool
+		Importable   bool
+		Funcs        []*crudFunc
+		Fields       map[string]string
+	}{
+		ResourceName: rg.ResourceName,
+		ContextFuncs: backend.ContextFuncs,
+		Importable:   rg.Importable,
+		Funcs:        funcs,
+		Fields:       b.fieldsCode(),
+	}
+
+	r := &Resource{
+		Imports:   append(append([]string{}, backend.ResourceImports...), backend.SchemaImports...),
+		CRUDFuncs: make(map[string]string, len(funcs)),
+	}
+	var err error
+	r.ResourceFunc, err = executeTemplate(resourceFuncTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range funcs {
+		name := fmt.Sprintf("resource%s%s", rg.ResourceName, f.Op)
+		r.CRUDFuncs[name], err = executeTemplate(crudFuncTemplate, struct {
+			*crudFunc
+			ResourceName string
+			ContextFuncs bool
+		}{f, rg.ResourceName, backend.ContextFuncs})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// isUpdatable is true if any field can be changed without recreating the resource
+func (b block) isUpdatable() bool {
+	for _, f := range b {
+		if (f.Schema.Required || f.Schema.Optional) && !f.Schema.ForceNew {
+			return true
+		}
+	}
+	return false
+}
+
+var resourceFuncTemplate = template.Must(template.New("resource").Parse(`func resource{{.ResourceName}}() *schema.Resource {
+return &schema.Resource{
+{{- range .Funcs}}
+{{.Op}}{{if $.ContextFuncs}}Context{{end}}: resource{{$.ResourceName}}{{.Op}},
+{{- end}}
+{{- if .Importable}}
+Importer: &schema.ResourceImporter{
+{{- if .ContextFuncs}}
+StateContext: schema.ImportStatePassthroughContext,
+{{- else}}
+State: schema.ImportStatePassthrough,
+{{- end}}
+},
+{{- end}}
+Schema: map[string]*schema.Schema{
+{{- range $name, $schema := .Fields}}
+{{printf "%q" $name}}: {{$schema}},
+{{- end}}
+},
+}
+}`))
+
+var crudFuncTemplate = template.Must(template.New("crud").Parse(`{{if .ContextFuncs -}}
+func resource{{.ResourceName}}{{.Op}}(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// TODO: {{.Comment}}, return diag.FromErr(err) on failure
+{{- if ne .Next ""}}
+return resource{{.ResourceName}}{{.Next}}(ctx, d, meta)
+{{- else}}
+return nil
+{{- end}}
+}
+{{- else -}}
+func resource{{.ResourceName}}{{.Op}}(d *schema.ResourceData, meta interface{}) error {
+// TODO: {{.Comment}}
+{{- if ne .Next ""}}
+return resource{{.ResourceName}}{{.Next}}(d, meta)
+{{- else}}
+return nil
+{{- end}}
+}
+{{- end}}`))
@@ -0,0 +1,161 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+type resourceBucket struct {
+	Name   string
+	Region string
+}
+
+func resourceGenerator(backend *backends.Backend, forceNewRegion bool) *ResourceGenerator {
+	return &ResourceGenerator{
+		ResourceName: "StorageBucket",
+		Schema: &SchemaGenerator{
+			DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+				return ""
+			},
+			FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+				if sf.Name == "Name" {
+					s.Required = true
+					s.ForceNew = true
+				} else {
+					s.Optional = true
+					s.ForceNew = forceNewRegion
+				}
+				return k, true
+			},
+			Backend: backend,
+		},
+		Importable: true,
+	}
+}
+
+func TestResourceGenerator_pluginSDKv2(t *testing.T) {
+	r, err := resourceGenerator(backends.PluginSDKv2, false).FromStruct(&resourceBucket{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedImports := []string{
+		"context",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/diag",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure",
+		"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation",
+	}
+	if !reflect.DeepEqual(r.Imports, expectedImports) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedImports, r.Imports)
+	}
+
+	expectedResourceFunc := `func resourceStorageBucket() *schema.Resource {
+return &schema.Resource{
+CreateContext: resourceStorageBucketCreate,
+ReadContext: resourceStorageBucketRead,
+UpdateContext: resourceStorageBucketUpdate,
+DeleteContext: resourceStorageBucketDelete,
+Importer: &schema.ResourceImporter{
+StateContext: schema.ImportStatePassthroughContext,
+},
+Schema: map[string]*schema.Schema{
+"name": {
+Type: schema.TypeString,
+Required: true,
+ForceNew: true,
+},
+"region": {
+Type: schema.TypeString,
+Optional: true,
+},
+},
+}
+}`
+	if r.ResourceFunc != expectedResourceFunc {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedResourceFunc, r.ResourceFunc)
+	}
+
+	expectedFuncs := map[string]string{
+		"resourceStorageBucketCreate": `func resourceStorageBucketCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// TODO: Create the resource and set its ID via d.SetId, return diag.FromErr(err) on failure
+return resourceStorageBucketRead(ctx, d, meta)
+}`,
+		"resourceStorageBucketRead": `func resourceStorageBucketRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// TODO: Read the resource and set its fields via d.Set (or unset the ID if it's gone), return diag.FromErr(err) on failure
+return nil
+}`,
+		"resourceStorageBucketUpdate": `func resourceStorageBucketUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// TODO: Update fields which have changed (see d.HasChange), return diag.FromErr(err) on failure
+return resourceStorageBucketRead(ctx, d, meta)
+}`,
+		"resourceStorageBucketDelete": `func resourceStorageBucketDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+// TODO: Delete the resource, return diag.FromErr(err) on failure
+return nil
+}`,
+	}
+	if !reflect.DeepEqual(r.CRUDFuncs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, r.CRUDFuncs)
+	}
+}
+
+func TestResourceGenerator_helperSchema(t *testing.T) {
+	r, err := resourceGenerator(nil, true).FromStruct(&resourceBucket{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResourceFunc := `func resourceStorageBucket() *schema.Resource {
+return &schema.Resource{
+Create: resourceStorageBucketCreate,
+Read: resourceStorageBucketRead,
+Delete: resourceStorageBucketDelete,
+Importer: &schema.ResourceImporter{
+State: schema.ImportStatePassthrough,
+},
+Schema: map[string]*schema.Schema{
+"name": {
+Type: schema.TypeString,
+Required: true,
+ForceNew: true,
+},
+"region": {
+Type: schema.TypeString,
+Optional: true,
+ForceNew: true,
+},
+},
+}
+}`
+	if r.ResourceFunc != expectedResourceFunc {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedResourceFunc, r.ResourceFunc)
+	}
+
+	expectedFuncs := map[string]string{
+		"resourceStorageBucketCreate": `func resourceStorageBucketCreate(d *schema.ResourceData, meta interface{}) error {
+// TODO: Create the resource and set its ID via d.SetId
+return resourceStorageBucketRead(d, meta)
+}`,
+		"resourceStorageBucketRead": `func resourceStorageBucketRead(d *schema.ResourceData, meta interface{}) error {
+// TODO: Read the resource and set its fields via d.Set (or unset the ID if it's gone)
+return nil
+}`,
+		"resourceStorageBucketDelete": `func resourceStorageBucketDelete(d *schema.ResourceData, meta interface{}) error {
+// TODO: Delete the resource
+return nil
+}`,
+	}
+	if !reflect.DeepEqual(r.CRUDFuncs, expectedFuncs) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFuncs, r.CRUDFuncs)
+	}
+}
+
+func TestResourceGenerator_framework(t *testing.T) {
+	_, err := resourceGenerator(backends.Framework, false).FromStruct(&resourceBucket{})
+	if err == nil {
+		t.Fatal("Expected error for the framework backend")
+	}
+}
@@ -0,0 +1,173 @@
+package schemagen
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type ChangeKind string
+
+const (
+	FieldAdded      ChangeKind = "added"
+	FieldRemoved    ChangeKind = "removed"
+	TypeChanged     ChangeKind = "type-changed"
+	RequiredChanged ChangeKind = "required-changed"
+	ForceNewChanged ChangeKind = "force-new-changed"
+)
+
+// SchemaChange describes a single difference between two versions of a schema.
+// Breaking changes are those which make existing state or configuration invalid.
+type SchemaChange struct {
+	// Path is the dot-separated path of the field, e.g. spec.container.name
+	Path     string
+	Kind     ChangeKind
+	Old      *schema.Schema
+	New      *schema.Schema
+	Breaking bool
+	Message  string
+}
+
+func (sc *SchemaChange) String() string {
+	prefix := ""
+	if sc.Breaking {
+		prefix = "[BREAKING] "
+	}
+	return fmt.Sprintf("%s%s: %s", prefix, sc.Path, sc.Message)
+}
+
+// DiffSchemas compares two versions of the schema (e.g. one generated via SchemaFromStruct
+// from a new SDK and one of an existing resource) and returns all changes sorted by path
+func DiffSchemas(oldSchema, newSchema map[string]*schema.Schema) []*SchemaChange {
+	changes := diffSchemaMaps("", oldSchema, newSchema)
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// HasBreakingChanges is true if any of the changes is breaking
+func HasBreakingChanges(changes []*SchemaChange) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+func diffSchemaMaps(prefix string, oldSchema, newSchema map[string]*schema.Schema) []*SchemaChange {
+	changes := make([]*SchemaChange, 0)
+
+	for name, o := range oldSchema {
+		path := prefix + name
+		n, ok := newSchema[name]
+		if !ok {
+			changes = append(changes, &SchemaChange{
+				Path:     path,
+				Kind:     FieldRemoved,
+				Old:      o,
+				Breaking: true,
+				Message:  "field removed",
+			})
+			continue
+		}
+		changes = append(changes, diffSchema(path, o, n)...)
+	}
+
+	for name, n := range newSchema {
+		if _, ok := oldSchema[name]; ok {
+			continue
+		}
+		msg := "field added"
+		if n.Required {
+			msg = "required field added"
+		}
+		changes = append(changes, &SchemaChange{
+			Path:     prefix + name,
+			Kind:     FieldAdded,
+			New:      n,
+			Breaking: n.Required,
+			Message:  msg,
+		})
+	}
+
+	return changes
+}
+
+func diffSchema(path string, o, n *schema.Schema) []*SchemaChange {
+	changes := make([]*SchemaChange, 0)
+
+	oldType, newType := typeDescription(o), typeDescription(n)
+	if oldType != newType {
+		changes = append(changes, &SchemaChange{
+			Path:     path,
+			Kind:     TypeChanged,
+			Old:      o,
+			New:      n,
+			Breaking: true,
+			Message:  fmt.Sprintf("type changed from %s to %s", oldType, newType),
+		})
+		// Nested fields of different types are not comparable
+		return changes
+	}
+
+	if o.Required != n.Required {
+		changes = append(changes, &SchemaChange{
+			Path:     path,
+			Kind:     RequiredChanged,
+			Old:      o,
+			New:      n,
+			Breaking: n.Required,
+			Message:  fmt.Sprintf("changed from %s to %s", requiredness(o), requiredness(n)),
+		})
+	}
+
+	if o.ForceNew != n.ForceNew {
+		msg := "no longer forces new resource"
+		if n.ForceNew {
+			msg = "now forces new resource"
+		}
+		changes = append(changes, &SchemaChange{
+			Path:    path,
+			Kind:    ForceNewChanged,
+			Old:     o,
+			New:     n,
+			Message: msg,
+		})
+	}
+
+	oldRes, oldIsRes := o.Elem.(*schema.Resource)
+	newRes, newIsRes := n.Elem.(*schema.Resource)
+	if oldIsRes && newIsRes {
+		changes = append(changes, diffSchemaMaps(path+".", oldRes.Schema, newRes.Schema)...)
+	}
+
+	return changes
+}
+
+// typeDescription returns type including type of elements, e.g. TypeList(TypeString)
+func typeDescription(s *schema.Schema) string {
+	switch e := s.Elem.(type) {
+	case *schema.Schema:
+		return fmt.Sprintf("%s(%s)", s.Type, typeDescription(e))
+	case *schema.Resource:
+		return fmt.Sprintf("%s(Resource)", s.Type)
+	}
+	return s.Type.String()
+}
+
+func requiredness(s *schema.Schema) string {
+	switch {
+	case s.Required:
+		return "Required"
+	case s.Optional && s.Computed:
+		return "Optional+Computed"
+	case s.Optional:
+		return "Optional"
+	case s.Computed:
+		return "Computed"
+	}
+	return "unset"
+}
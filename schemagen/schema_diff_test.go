@@ -0,0 +1,112 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDiffSchemas_structVersions(t *testing.T) {
+	type NestedV1 struct {
+		Image string
+		Ports []int
+	}
+	type SpecV1 struct {
+		Name      string
+		Replicas  int
+		Hostname  string
+		Container *NestedV1
+	}
+	type NestedV2 struct {
+		Image string
+		Ports []string
+		Args  []string
+	}
+	type SpecV2 struct {
+		Name      string
+		Replicas  int
+		Subdomain string
+		Container *NestedV2
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterV1 := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+	filterV2 := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		switch sf.Name {
+		case "Name":
+			s.Required = true
+			s.ForceNew = true
+		case "Subdomain":
+			s.Required = true
+		default:
+			s.Optional = true
+		}
+		return k, true
+	}
+
+	oldSchema := (&SchemaGenerator{DocsFunc: docsF, FilterFunc: filterV1}).SchemaFromStruct(&SpecV1{})
+	newSchema := (&SchemaGenerator{DocsFunc: docsF, FilterFunc: filterV2}).SchemaFromStruct(&SpecV2{})
+
+	changes := DiffSchemas(oldSchema, newSchema)
+	output := make([]string, len(changes), len(changes))
+	for i, c := range changes {
+		output[i] = c.String()
+	}
+	expectedOutput := []string{
+		"container.args: field added",
+		"[BREAKING] container.ports: type changed from TypeSet(TypeInt) to TypeSet(TypeString)",
+		"[BREAKING] hostname: field removed",
+		"[BREAKING] name: changed from Optional to Required",
+		"name: now forces new resource",
+		"[BREAKING] subdomain: required field added",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedOutput, output)
+	}
+	if !HasBreakingChanges(changes) {
+		t.Fatal("Expected breaking changes")
+	}
+}
+
+func TestDiffSchemas_nonBreaking(t *testing.T) {
+	oldSchema := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+	}
+	newSchema := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"labels": {
+			Type:     schema.TypeMap,
+			Optional: true,
+		},
+	}
+
+	changes := DiffSchemas(oldSchema, newSchema)
+	output := make([]string, len(changes), len(changes))
+	for i, c := range changes {
+		output[i] = c.String()
+	}
+	expectedOutput := []string{
+		"labels: field added",
+		"name: changed from Required to Optional",
+		"name: no longer forces new resource",
+	}
+	if !reflect.DeepEqual(output, expectedOutput) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedOutput, output)
+	}
+	if HasBreakingChanges(changes) {
+		t.Fatal("Expected no breaking changes")
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"regexp"
 	"sort"
 	"text/template"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	"github.com/radeksimko/terraform-gen/converters"
 	u "github.com/radeksimko/terraform-gen/internal/util"
 )
 
@@ -18,120 +21,243 @@ type filterFunc func(iface interface{}, sf *reflect.StructField, k reflect.Kind,
 type SchemaGenerator struct {
 	DocsFunc   getDocsFunc
 	FilterFunc filterFunc
+	// DefaultFunc returns raw default value of the field (e.g. "80"), if any.
+	// Struct tag takes precedence, "Defaults to X" in docs is used as a fallback.
+	DefaultFunc getDefaultFunc
+	// SensitivePatterns match underscored names of sensitive fields (defaults to DefaultSensitivePatterns)
+	SensitivePatterns []*regexp.Regexp
+	// SensitiveFunc may mark other fields as sensitive, struct tag takes precedence
+	SensitiveFunc sensitiveFunc
+	// Unions holds kinds of union (one-of) structs, in addition to those
+	// recognised by the struct tag or "only one of" in docs
+	Unions map[reflect.Type]UnionKind
+	// Converters defines representation of special types (defaults to converters.NewBuiltinRegistry)
+	Converters *converters.Registry
+	// Backend is the plugin SDK targeted by generated code (defaults to backends.HelperSchema)
+	Backend *backends.Backend
 }
 
+// field is an intermediate representation of a schema field,
+// which can be rendered as code or converted into *schema.Schema
+type field struct {
+	// Schema carries type, flags & description, Elem is never set here
+	Schema  *schema.Schema
+	SetFunc string
+	// ValidateFunc is code of the validation function (it's not converted by schema())
+	ValidateFunc string
+	// StateFunc & DiffSuppressFunc are code of the functions (not converted by schema() either)
+	StateFunc        string
+	DiffSuppressFunc string
+	// Elem is either *field (for primitive elements) or block (for nested resource)
+	Elem interface{}
+	// Union is kind of the nested block, if it's a union struct
+	Union UnionKind
+	// ExactlyOneOf is only rendered as code (it's not converted by schema())
+	ExactlyOneOf []string
+	// GoName is name of the struct field & GoType is type of the nested struct (if any),
+	// both used to generate framework models
+	GoName string
+	GoType reflect.Type
+}
+
+type block map[string]*field
+
 func (g *SchemaGenerator) FromStruct(iface interface{}) map[string]string {
+	b := g.rootBlock(iface)
+	if g.backend().Framework {
+		attributes, blocks := b.frameworkFieldsCode()
+		for name, content := range blocks {
+			attributes[name] = content
+		}
+		return attributes
+	}
+	return b.fieldsCode()
+}
+
+// SchemaFromStruct works like FromStruct, but returns actual schema
+// instead of code, e.g. for comparison with schema of an existing resource
+func (g *SchemaGenerator) SchemaFromStruct(iface interface{}) map[string]*schema.Schema {
+	return g.rootBlock(iface).schemaMap()
+}
+
+func (g *SchemaGenerator) rootBlock(iface interface{}) block {
+	b := g.blockFromStruct(iface)
+	b.linkUnions("", g.unionKind(reflect.TypeOf(iface), nil, ""), g.backend().ExactlyOneOf)
+	return b
+}
+
+func (g *SchemaGenerator) blockFromStruct(iface interface{}) block {
 	rawType := u.DereferencePtrType(reflect.TypeOf(iface))
-	fields := make(map[string]string, 0)
+	fields := make(block, 0)
 
 	for i := 0; i < rawType.NumField(); i++ {
 		sf := rawType.Field(i)
 
-		content, err := g.generateField(sf.Name, sf.Type, iface, &sf, false)
+		f, err := g.generateField(sf.Name, sf.Type, iface, &sf)
 		if err != nil {
 			log.Printf("ERROR: %s", err)
 		} else {
-			fields[u.Underscore(sf.Name)] = content
+			fields[u.Underscore(sf.Name)] = f
 		}
 	}
 
 	return fields
 }
 
-func (g *SchemaGenerator) generateField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField, isNested bool) (string, error) {
+func (g *SchemaGenerator) generateField(sfName string, sfType reflect.Type, iface interface{}, sf *reflect.StructField) (*field, error) {
 	kind := u.DereferencePtrType(sfType).Kind()
-	var comment, setFunc string
-	s := &schema.Schema{}
+	var comment string
+	f := &field{Schema: &schema.Schema{}}
+	s := f.Schema
 
 	if sf != nil {
 		var ok bool
 		kind, ok = g.FilterFunc(iface, sf, kind, s)
 		if !ok {
-			return "", fmt.Errorf("Skipping %q (filter)", sf.Name)
+			return nil, fmt.Errorf("Skipping %q (filter)", sf.Name)
 		}
 		comment = g.DocsFunc(iface, sf)
 	}
 
-	switch kind {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		s.Type = schema.TypeInt
-	case reflect.Float32, reflect.Float64:
-		s.Type = schema.TypeFloat
-	case reflect.String:
-		s.Type = schema.TypeString
-	case reflect.Bool:
-		s.Type = schema.TypeBool
-	case reflect.Slice:
-		// TODO: TypeList may be more suitable for some situations
-		// TODO: Proper SetFunc may be required for TypeSet
-		s.Type = schema.TypeSet
-		elem, err := g.generateField("", sfType.Elem(), iface, nil, true)
-		if err != nil {
-			return "", fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
+	c, ok := g.converters().Lookup(sfType)
+	if !ok {
+		c, ok = g.converters().Lookup(u.DereferencePtrType(sfType))
+	}
+	if ok {
+		s.Type = c.SchemaType
+		f.ValidateFunc = c.ValidateFunc
+		if c.ValidateRFC3339 {
+			f.ValidateFunc = g.backend().ValidateRFC3339Func
 		}
-		s.Elem = elem
+	} else if u.IsJSONType(sfType) {
+		g.setJSON(f)
+	} else {
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			s.Type = schema.TypeInt
+		case reflect.Float32, reflect.Float64:
+			s.Type = schema.TypeFloat
+		case reflect.String:
+			s.Type = schema.TypeString
+		case reflect.Bool:
+			s.Type = schema.TypeBool
+		case reflect.Slice:
+			if u.IsByteSlice(u.DereferencePtrType(sfType)) {
+				s.Type = schema.TypeString
+				if u.BytesEncoding(sf) == u.Base64Encoding {
+					f.ValidateFunc = g.backend().ValidateBase64Func
+				}
+				break
+			}
+			// TODO: TypeList may be more suitable for some situations
+			// TODO: Proper SetFunc may be required for TypeSet
+			s.Type = schema.TypeSet
+			elem, err := g.generateElem(sfType.Elem(), iface)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
+			}
+			f.Elem = elem
+			if _, ok := elem.(block); ok {
+				f.GoType = u.DereferencePtrType(sfType.Elem())
+			}
 
-		elemKind := u.DereferencePtrType(sfType.Elem()).Kind()
-		if elemKind == reflect.String {
-			setFunc = "schema.HashString"
-		}
-	case reflect.Map:
-		s.Type = schema.TypeMap
-		// TODO: Elem(map[string]string)
-		// TODO: Elem(map[string]int)
-		// TODO: Elem(map[string]bool)
-		// TODO: Elem(map[string]float)
-	case reflect.Struct:
-		structType := sfType
-		if structType.Kind() == reflect.Ptr {
-			structType = structType.Elem()
+			elemKind := u.DereferencePtrType(sfType.Elem()).Kind()
+			if elemKind == reflect.String {
+				f.SetFunc = "schema.HashString"
+			}
+		case reflect.Map:
+			s.Type = schema.TypeMap
+			err := g.setMapElem(f, sfType, iface, sf)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to generate Elem for %q: %s", sfName, err)
+			}
+		case reflect.Struct:
+			s.Type = schema.TypeList
+			s.MaxItems = 1
+			f.Elem = g.blockFromStructType(sfType)
+			f.GoType = u.DereferencePtrType(sfType)
+			f.Union = g.unionKind(sfType, sf, comment)
+		default:
+			f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
+			return nil, fmt.Errorf("Unable to process: %s", f)
 		}
+	}
 
-		s.Type = schema.TypeList
-		s.MaxItems = 1
+	s.Description = comment
+	f.GoName = sfName
+	if sf != nil {
+		g.setDefault(iface, sf, s)
+		g.setSensitive(iface, sf, s)
+	}
 
-		elem := "&schema.Resource{\nSchema: map[string]*schema.Schema{\n"
+	return f, nil
+}
 
-		iface := reflect.New(structType).Elem().Interface()
+func (g *SchemaGenerator) backend() *backends.Backend {
+	return backendOrDefault(g.Backend)
+}
 
-		m := g.FromStruct(iface)
-		fieldNames := make([]string, len(m), len(m))
-		i := 0
-		for k, _ := range m {
-			fieldNames[i] = k
-			i++
-		}
-		sort.Strings(fieldNames)
-		for _, k := range fieldNames {
-			elem += fmt.Sprintf("%q: %s,\n", k, m[k])
-		}
-		elem += "},\n}"
-		if isNested {
-			return elem, nil
-		}
+// backendOrDefault returns the backend, or helper/schema if none is set
+func backendOrDefault(b *backends.Backend) *backends.Backend {
+	if b == nil {
+		return backends.HelperSchema
+	}
+	return b
+}
 
-		s.Elem = elem
-	default:
-		f := fmt.Sprintf("%s %s\n", sfName, sfType.String())
-		return "", fmt.Errorf("Unable to process: %s", f)
+func (g *SchemaGenerator) converters() *converters.Registry {
+	if g.Converters == nil {
+		g.Converters = converters.NewBuiltinRegistry()
 	}
+	return g.Converters
+}
 
-	s.Description = comment
+// generateElem returns either *field or block for nested structs
+func (g *SchemaGenerator) generateElem(elemType reflect.Type, iface interface{}) (interface{}, error) {
+	if u.DereferencePtrType(elemType).Kind() == reflect.Struct {
+		return g.blockFromStructType(elemType), nil
+	}
+	return g.generateField("", elemType, iface, nil)
+}
 
-	return schemaCode(s, setFunc, isNested)
+func (g *SchemaGenerator) blockFromStructType(structType reflect.Type) block {
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	iface := reflect.New(structType).Elem().Interface()
+	return g.blockFromStruct(iface)
 }
 
-func schemaCode(s *schema.Schema, setFunc string, isNested bool) (string, error) {
+func (f *field) code(isNested bool) (string, error) {
+	elem, err := elemCode(f.Elem)
+	if err != nil {
+		return "", err
+	}
+
 	buf := bytes.NewBuffer([]byte{})
-	err := schemaTemplate.Execute(buf, struct {
-		Schema   *schema.Schema
-		SetFunc  string
-		IsNested bool
+	err = schemaTemplate.Execute(buf, struct {
+		Schema           *schema.Schema
+		SetFunc          string
+		ValidateFunc     string
+		StateFunc        string
+		DiffSuppressFunc string
+		Default          string
+		ConflictsWith    string
+		ExactlyOneOf     string
+		Elem             string
+		IsNested         bool
 	}{
-		Schema:   s,
-		SetFunc:  setFunc,
-		IsNested: isNested,
+		Schema:           f.Schema,
+		SetFunc:          f.SetFunc,
+		ValidateFunc:     f.ValidateFunc,
+		StateFunc:        f.StateFunc,
+		DiffSuppressFunc: f.DiffSuppressFunc,
+		Default:          defaultLiteral(f.Schema.Default),
+		ConflictsWith:    stringsLiteral(f.Schema.ConflictsWith),
+		ExactlyOneOf:     stringsLiteral(f.ExactlyOneOf),
+		Elem:             elem,
+		IsNested:         isNested,
 	})
 	if err != nil {
 		return "", err
@@ -140,14 +266,94 @@ func schemaCode(s *schema.Schema, setFunc string, isNested bool) (string, error)
 	return buf.String(), nil
 }
 
+func elemCode(elem interface{}) (string, error) {
+	switch e := elem.(type) {
+	case *field:
+		return e.code(true)
+	case block:
+		return e.code()
+	}
+	return "", nil
+}
+
+// fieldsCode returns code of each field, skipping those which cannot be rendered
+func (b block) fieldsCode() map[string]string {
+	fields := make(map[string]string, 0)
+	for name, f := range b {
+		content, err := f.code(false)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+		fields[name] = content
+	}
+
+	return fields
+}
+
+func (b block) code() (string, error) {
+	code := "&schema.Resource{\nSchema: map[string]*schema.Schema{\n"
+
+	fieldNames := make([]string, len(b), len(b))
+	i := 0
+	for k, _ := range b {
+		fieldNames[i] = k
+		i++
+	}
+	sort.Strings(fieldNames)
+	for _, k := range fieldNames {
+		content, err := b[k].code(false)
+		if err != nil {
+			return "", err
+		}
+		code += fmt.Sprintf("%q: %s,\n", k, content)
+	}
+
+	return code + "},\n}", nil
+}
+
+// knownSetFuncs maps SetFunc code to the actual function
+var knownSetFuncs = map[string]schema.SchemaSetFunc{
+	"schema.HashString": schema.HashString,
+}
+
+func (f *field) schema() *schema.Schema {
+	s := *f.Schema
+	switch e := f.Elem.(type) {
+	case *field:
+		s.Elem = e.schema()
+	case block:
+		s.Elem = &schema.Resource{Schema: e.schemaMap()}
+	}
+	if f.SetFunc != "" {
+		s.Set = knownSetFuncs[f.SetFunc]
+	}
+	return &s
+}
+
+func (b block) schemaMap() map[string]*schema.Schema {
+	m := make(map[string]*schema.Schema, len(b))
+	for name, f := range b {
+		m[name] = f.schema()
+	}
+	return m
+}
+
 var schemaTemplate = template.Must(template.New("schema").Parse(`{{if .IsNested}}&schema.Schema{{end}}{{"{"}}{{if not .IsNested}}
 {{end}}Type: schema.{{.Schema.Type}},{{if ne .Schema.Description ""}}
 Description: {{printf "%q" .Schema.Description}},{{end}}{{if .Schema.Required}}
 Required: {{.Schema.Required}},{{end}}{{if .Schema.Optional}}
-Optional: {{.Schema.Optional}},{{end}}{{if .Schema.ForceNew}}
+Optional: {{.Schema.Optional}},{{end}}{{if ne .Default ""}}
+Default: {{.Default}},{{end}}{{if .Schema.ForceNew}}
 ForceNew: {{.Schema.ForceNew}},{{end}}{{if .Schema.Computed}}
-Computed: {{.Schema.Computed}},{{end}}{{if gt .Schema.MaxItems 0}}
-MaxItems: {{.Schema.MaxItems}},{{end}}{{if .Schema.Elem}}
-Elem: {{.Schema.Elem}},{{end}}{{if ne .SetFunc ""}}{{if not .IsNested}}
+Computed: {{.Schema.Computed}},{{end}}{{if .Schema.Sensitive}}
+Sensitive: {{.Schema.Sensitive}},{{end}}{{if ne .ConflictsWith ""}}
+ConflictsWith: {{.ConflictsWith}},{{end}}{{if ne .ExactlyOneOf ""}}
+ExactlyOneOf: {{.ExactlyOneOf}},{{end}}{{if gt .Schema.MaxItems 0}}
+MaxItems: {{.Schema.MaxItems}},{{end}}{{if ne .ValidateFunc ""}}
+ValidateFunc: {{.ValidateFunc}},{{end}}{{if ne .StateFunc ""}}
+StateFunc: {{.StateFunc}},{{end}}{{if ne .DiffSuppressFunc ""}}
+DiffSuppressFunc: {{.DiffSuppressFunc}},{{end}}{{if ne .Elem ""}}
+Elem: {{.Elem}},{{end}}{{if ne .SetFunc ""}}{{if not .IsNested}}
 {{end}}Set: {{.SetFunc}},{{end}}{{if not .IsNested}}
 {{end}}{{"}"}}`))
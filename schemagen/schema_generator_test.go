@@ -3,8 +3,11 @@ package schemagen
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	"github.com/radeksimko/terraform-gen/converters"
 )
 
 func TestGenerateField_primitive(t *testing.T) {
@@ -254,3 +257,125 @@ func TestGenerateField_sliceOfStructs(t *testing.T) {
 		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedSchema, schema)
 	}
 }
+
+func TestSchemaFromStruct(t *testing.T) {
+	type NestedStruct struct {
+		MyInt int
+	}
+	type SimpleStruct struct {
+		Nested   *NestedStruct
+		MyString []string
+	}
+
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return "Docs for " + sf.Name
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	m := g.SchemaFromStruct(&SimpleStruct{})
+
+	nested := m["nested"]
+	if nested.Type != schema.TypeList || nested.MaxItems != 1 || nested.Description != "Docs for Nested" {
+		t.Fatalf("Unexpected schema of nested: %#v", nested)
+	}
+	res, ok := nested.Elem.(*schema.Resource)
+	if !ok {
+		t.Fatalf("Expected *schema.Resource as Elem, given: %#v", nested.Elem)
+	}
+	if res.Schema["my_int"].Type != schema.TypeInt || !res.Schema["my_int"].Optional {
+		t.Fatalf("Unexpected schema of nested.my_int: %#v", res.Schema["my_int"])
+	}
+
+	myString := m["my_string"]
+	if myString.Type != schema.TypeSet || myString.Set == nil {
+		t.Fatalf("Unexpected schema of my_string: %#v", myString)
+	}
+	if elem, ok := myString.Elem.(*schema.Schema); !ok || elem.Type != schema.TypeString {
+		t.Fatalf("Expected TypeString as Elem, given: %#v", myString.Elem)
+	}
+}
+
+func TestGenerateField_converters(t *testing.T) {
+	type Quantity struct {
+		Value string
+	}
+	type SimpleStruct struct {
+		CreatedAt time.Time
+		DeletedAt *time.Time
+		Timeout   time.Duration
+		Memory    Quantity
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		return k, true
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+
+	r := converters.NewBuiltinRegistry()
+	r.Register(Quantity{}, &converters.Converter{
+		SchemaType: schema.TypeString,
+		Expander:   "resource.MustParse(%s)",
+		Flattener:  "%s.String()",
+	})
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF, Converters: r}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"created_at": "{\nType: schema.TypeString,\nValidateFunc: validation.ValidateRFC3339TimeString,\n}",
+		"deleted_at": "{\nType: schema.TypeString,\nValidateFunc: validation.ValidateRFC3339TimeString,\n}",
+		"timeout":    "{\nType: schema.TypeString,\nValidateFunc: " + converters.DurationValidateFunc + ",\n}",
+		"memory":     "{\nType: schema.TypeString,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	g.Backend = backends.PluginSDKv2
+	fields = g.FromStruct(&SimpleStruct{})
+	expectedFields["created_at"] = "{\nType: schema.TypeString,\nValidateFunc: validation.IsRFC3339Time,\n}"
+	expectedFields["deleted_at"] = "{\nType: schema.TypeString,\nValidateFunc: validation.IsRFC3339Time,\n}"
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
+
+func TestGenerateField_bytes(t *testing.T) {
+	type Certificate []byte
+	type SimpleStruct struct {
+		Key         []byte
+		Certificate Certificate
+		Script      []byte `terraform-gen:"bytes=raw"`
+		Data        map[string][]byte
+	}
+	docsF := func(_struct interface{}, sf *reflect.StructField) string {
+		return ""
+	}
+	filterF := func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+		s.Optional = true
+		return k, true
+	}
+
+	g := &SchemaGenerator{DocsFunc: docsF, FilterFunc: filterF}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"key":         "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"certificate": "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"script":      "{\nType: schema.TypeString,\nOptional: true,\n}",
+		"data":        "{\nType: schema.TypeMap,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	g.Backend = backends.PluginSDKv2
+	fields = g.FromStruct(&SimpleStruct{})
+	expectedFields["key"] = "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.StringIsBase64,\n}"
+	expectedFields["certificate"] = "{\nType: schema.TypeString,\nOptional: true,\nValidateFunc: validation.StringIsBase64,\n}"
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
@@ -0,0 +1,69 @@
+package schemagen
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+type sensitiveFunc func(iface interface{}, sf *reflect.StructField, s *schema.Schema) bool
+
+// DefaultSensitivePatterns match (underscored) names of fields
+// holding secrets, e.g. admin_password, client_secret or private_key
+var DefaultSensitivePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(^|_)(password|passwd|secret|token|private_key|api_key)$`),
+}
+
+// setSensitive marks the field as sensitive based on (in order of precedence)
+// the struct tag (sensitive or sensitive=false), SensitiveFunc, name patterns
+// and data of Kubernetes Secrets
+func (g *SchemaGenerator) setSensitive(iface interface{}, sf *reflect.StructField, s *schema.Schema) {
+	if raw, ok := u.TagOptions(sf)["sensitive"]; ok {
+		sensitive, err := strconv.ParseBool(raw)
+		s.Sensitive = raw == "" || (err == nil && sensitive)
+		return
+	}
+	if g.SensitiveFunc != nil && g.SensitiveFunc(iface, sf, s) {
+		s.Sensitive = true
+		return
+	}
+	if isKubernetesSecretData(iface, sf) {
+		s.Sensitive = true
+		return
+	}
+	// names of lists & sets (e.g. secret volume source) don't say much about their items
+	if !isListOrSet(s) {
+		s.Sensitive = isSensitiveName(u.Underscore(sf.Name), g.sensitivePatterns())
+	}
+}
+
+func (g *SchemaGenerator) sensitivePatterns() []*regexp.Regexp {
+	if g.SensitivePatterns == nil {
+		return DefaultSensitivePatterns
+	}
+	return g.SensitivePatterns
+}
+
+func isListOrSet(s *schema.Schema) bool {
+	return s.Type == schema.TypeList || s.Type == schema.TypeSet
+}
+
+func isSensitiveName(name string, patterns []*regexp.Regexp) bool {
+	for _, p := range patterns {
+		if p.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isKubernetesSecretData is true for data & stringData of a Secret
+func isKubernetesSecretData(iface interface{}, sf *reflect.StructField) bool {
+	if iface == nil || u.DereferencePtrType(reflect.TypeOf(iface)).Name() != "Secret" {
+		return false
+	}
+	return sf.Name == "Data" || sf.Name == "StringData"
+}
@@ -0,0 +1,131 @@
+package schemagen
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestGenerateField_sensitive(t *testing.T) {
+	type SimpleStruct struct {
+		AdminPassword string
+		ClientSecret  string
+		SecretName    string
+		AccessToken   string
+		PrivateKey    string
+		Notes         string `terraform-gen:"sensitive"`
+		Token         string `terraform-gen:"sensitive=false"`
+		Pin           int
+	}
+	g := &SchemaGenerator{
+		DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+			return ""
+		},
+		FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			s.Optional = true
+			return k, true
+		},
+		SensitiveFunc: func(iface interface{}, sf *reflect.StructField, s *schema.Schema) bool {
+			return sf.Name == "Pin"
+		},
+	}
+	fields := g.FromStruct(&SimpleStruct{})
+
+	sensitiveString := "{\nType: schema.TypeString,\nOptional: true,\nSensitive: true,\n}"
+	plainString := "{\nType: schema.TypeString,\nOptional: true,\n}"
+	expectedFields := map[string]string{
+		"admin_password": sensitiveString,
+		"client_secret":  sensitiveString,
+		"secret_name":    plainString,
+		"access_token":   sensitiveString,
+		"private_key":    sensitiveString,
+		"notes":          sensitiveString,
+		"token":          plainString,
+		"pin":            "{\nType: schema.TypeInt,\nOptional: true,\nSensitive: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+
+	s := g.SchemaFromStruct(&SimpleStruct{})
+	if !s["admin_password"].Sensitive || s["secret_name"].Sensitive {
+		t.Fatalf("Unexpected sensitivity: %#v", s)
+	}
+}
+
+func TestGenerateField_sensitivePatterns(t *testing.T) {
+	type SimpleStruct struct {
+		Password string
+		Passcode string
+	}
+	g := &SchemaGenerator{
+		DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+			return ""
+		},
+		FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			return k, true
+		},
+		SensitivePatterns: []*regexp.Regexp{regexp.MustCompile(`^passcode$`)},
+	}
+	fields := g.FromStruct(&SimpleStruct{})
+	expectedFields := map[string]string{
+		"password": "{\nType: schema.TypeString,\n}",
+		"passcode": "{\nType: schema.TypeString,\nSensitive: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
+
+func TestGenerateField_kubernetesSecretData(t *testing.T) {
+	type Secret struct {
+		Type       string
+		Data       map[string]string
+		StringData map[string]string
+	}
+	g := &SchemaGenerator{
+		DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+			return ""
+		},
+		FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			return k, true
+		},
+	}
+	fields := g.FromStruct(&Secret{})
+	expectedFields := map[string]string{
+		"type":        "{\nType: schema.TypeString,\n}",
+		"data":        "{\nType: schema.TypeMap,\nSensitive: true,\n}",
+		"string_data": "{\nType: schema.TypeMap,\nSensitive: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
+
+func TestJSONSchemaGenerator_sensitive(t *testing.T) {
+	js, err := ParseJSONSchema([]byte(`{
+  "type": "object",
+  "properties": {
+    "apiKey": {"type": "string"},
+    "passphrase": {"type": "string", "format": "password"},
+    "username": {"type": "string"}
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := (&JSONSchemaGenerator{Schema: js}).FromDefinition("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFields := map[string]string{
+		"api_key":    "{\nType: schema.TypeString,\nOptional: true,\nSensitive: true,\n}",
+		"passphrase": "{\nType: schema.TypeString,\nOptional: true,\nSensitive: true,\n}",
+		"username":   "{\nType: schema.TypeString,\nOptional: true,\n}",
+	}
+	if !reflect.DeepEqual(fields, expectedFields) {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedFields, fields)
+	}
+}
@@ -0,0 +1,362 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// specSchema is a (subset of) schema object shared by OpenAPI and JSON Schema
+type specSchema struct {
+	Ref                  string                 `json:"$ref"`
+	AllOf                []*specSchema          `json:"allOf"`
+	Type                 specType               `json:"type"`
+	Format               string                 `json:"format"`
+	Description          string                 `json:"description"`
+	Required             []string               `json:"required"`
+	ReadOnly             bool                   `json:"readOnly"`
+	Default              interface{}            `json:"default"`
+	Enum                 []interface{}          `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Properties           map[string]*specSchema `json:"properties"`
+	Items                *specSchema            `json:"items"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+}
+
+// specType is the type of a schema object, which may be
+// a list of types in JSON Schema, e.g. ["string", "null"]
+type specType string
+
+func (t *specType) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = specType(single)
+		return nil
+	}
+
+	var types []string
+	if err := json.Unmarshal(b, &types); err != nil {
+		return err
+	}
+	for _, typ := range types {
+		if typ != "null" {
+			*t = specType(typ)
+			return nil
+		}
+	}
+	return nil
+}
+
+// specConverter converts spec schemas into fields & blocks
+type specConverter struct {
+	definitions map[string]*specSchema
+	// refPrefixes are prefixes of local references to definitions
+	refPrefixes []string
+	// root is the target of "#" references (JSON Schema only)
+	root *specSchema
+	// uniqueItemsAsSet makes only arrays with uniqueItems TypeSet (TypeList otherwise)
+	uniqueItemsAsSet bool
+	// backend provides validation functions differing between SDKs
+	backend *backends.Backend
+
+	// resolving holds definitions being converted, to detect circular references
+	resolving map[string]bool
+}
+
+func (c *specConverter) blockFromDefinition(name string, def *specSchema) block {
+	c.resolving = map[string]bool{name: true}
+	return c.blockFromSchema(def)
+}
+
+func (c *specConverter) blockFromSchema(s *specSchema) block {
+	required := make(map[string]bool, len(s.Required))
+	for _, name := range s.Required {
+		required[name] = true
+	}
+
+	fields := make(block, 0)
+	for name, prop := range s.Properties {
+		f, err := c.generateField(name, prop)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			continue
+		}
+
+		// readOnly fields are only returned by the API, even if required
+		if !f.Schema.Computed {
+			f.Schema.Required = required[name]
+			f.Schema.Optional = !required[name]
+		}
+		if f.Schema.Required || f.Schema.Computed {
+			f.Schema.Default = nil
+		}
+		fields[u.Underscore(name)] = f
+	}
+
+	return fields
+}
+
+func (c *specConverter) generateField(name string, s *specSchema) (*field, error) {
+	s, refName, err := c.resolve(s)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to resolve %q: %s", name, err)
+	}
+	if refName != "" {
+		if c.resolving[refName] {
+			return nil, fmt.Errorf("Skipping %q (circular reference to %q)", name, refName)
+		}
+		c.resolving[refName] = true
+		defer delete(c.resolving, refName)
+	}
+
+	f := &field{Schema: &schema.Schema{
+		Description: s.Description,
+		Computed:    s.ReadOnly,
+	}}
+
+	switch s.schemaType() {
+	case "integer":
+		f.Schema.Type = schema.TypeInt
+	case "number":
+		f.Schema.Type = schema.TypeFloat
+	case "string":
+		f.Schema.Type = schema.TypeString
+	case "boolean":
+		f.Schema.Type = schema.TypeBool
+	case "array":
+		if s.Items == nil {
+			return nil, fmt.Errorf("Unable to process: %s (array without items)", name)
+		}
+		f.Schema.Type = schema.TypeSet
+		if c.uniqueItemsAsSet && !s.UniqueItems {
+			f.Schema.Type = schema.TypeList
+		}
+		elem, err := c.generateElem(name, s.Items)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to generate Elem for %q: %s", name, err)
+		}
+		f.Elem = elem
+		if ef, ok := elem.(*field); ok && ef.Schema.Type == schema.TypeString && f.Schema.Type == schema.TypeSet {
+			f.SetFunc = "schema.HashString"
+		}
+	case "object":
+		if len(s.Properties) == 0 {
+			f.Schema.Type = schema.TypeMap
+			if ap := s.additionalProperties(); ap != nil {
+				elem, err := c.generateElem(name, ap)
+				if err != nil {
+					return nil, fmt.Errorf("Unable to generate Elem for %q: %s", name, err)
+				}
+				if ef, ok := elem.(*field); ok {
+					f.Elem = ef
+				}
+			}
+			break
+		}
+		f.Schema.Type = schema.TypeList
+		f.Schema.MaxItems = 1
+		f.Elem = c.blockFromSchema(s)
+	default:
+		return nil, fmt.Errorf("Unable to process: %s (type %q)", name, s.Type)
+	}
+
+	f.ValidateFunc = s.validateFunc(c.backend)
+	f.Schema.Sensitive = s.Format == "password" ||
+		(!isListOrSet(f.Schema) && isSensitiveName(u.Underscore(name), DefaultSensitivePatterns))
+	if s.Default != nil {
+		v, err := typedDefault(f.Schema.Type, fmt.Sprintf("%v", s.Default))
+		if err != nil {
+			log.Printf("Ignoring default of %q: %s", name, err)
+		} else {
+			f.Schema.Default = v
+		}
+	}
+
+	return f, nil
+}
+
+// generateElem returns either *field or block for nested objects
+func (c *specConverter) generateElem(name string, s *specSchema) (interface{}, error) {
+	elem, err := c.generateField(name, s)
+	if err != nil {
+		return nil, err
+	}
+	if elem.Schema.Type == schema.TypeList && elem.Schema.MaxItems == 1 {
+		return elem.Elem, nil
+	}
+	// Flags & descriptions are not applicable to elements
+	elem.Schema = &schema.Schema{Type: elem.Schema.Type}
+	return elem, nil
+}
+
+// resolve follows local references (incl. allOf with a single reference)
+// and returns the referenced schema with the name of its definition
+func (c *specConverter) resolve(s *specSchema) (*specSchema, string, error) {
+	if s.Ref == "" && len(s.AllOf) == 1 && s.Type == "" && len(s.Properties) == 0 {
+		resolved, refName, err := c.resolve(s.AllOf[0])
+		if err != nil {
+			return nil, "", err
+		}
+		if s.Description != "" || s.ReadOnly {
+			copied := *resolved
+			if s.Description != "" {
+				copied.Description = s.Description
+			}
+			copied.ReadOnly = copied.ReadOnly || s.ReadOnly
+			resolved = &copied
+		}
+		return resolved, refName, nil
+	}
+	if s.Ref == "" {
+		return s, "", nil
+	}
+
+	var def *specSchema
+	refName := s.Ref
+	if refName == "#" && c.root != nil {
+		def = c.root
+	} else {
+		for _, prefix := range c.refPrefixes {
+			if strings.HasPrefix(s.Ref, prefix) {
+				refName = strings.TrimPrefix(s.Ref, prefix)
+				var ok bool
+				def, ok = c.definitions[refName]
+				if !ok {
+					return nil, "", fmt.Errorf("Definition %q not found", refName)
+				}
+			}
+		}
+	}
+	if def == nil {
+		return nil, "", fmt.Errorf("Unsupported reference %q", s.Ref)
+	}
+
+	if s.Description != "" && def.Description == "" {
+		copied := *def
+		copied.Description = s.Description
+		def = &copied
+	}
+	return def, refName, nil
+}
+
+func (s *specSchema) schemaType() specType {
+	if s.Type == "" && len(s.Properties) > 0 {
+		return "object"
+	}
+	return s.Type
+}
+
+// additionalProperties returns schema of map values, if it is defined
+func (s *specSchema) additionalProperties() *specSchema {
+	if len(s.AdditionalProperties) == 0 {
+		return nil
+	}
+	var ap specSchema
+	err := json.Unmarshal(s.AdditionalProperties, &ap)
+	if err != nil {
+		// additionalProperties: true
+		return nil
+	}
+	return &ap
+}
+
+// validateFunc returns code of validation based on enum, format,
+// pattern, minLength/maxLength & minimum/maximum
+func (s *specSchema) validateFunc(b *backends.Backend) string {
+	validators := make([]string, 0)
+
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 {
+			values := make([]string, len(s.Enum), len(s.Enum))
+			for i, v := range s.Enum {
+				values[i] = strconv.Quote(fmt.Sprintf("%v", v))
+			}
+			validators = append(validators, fmt.Sprintf("validation.StringInSlice([]string{%s}, false)",
+				strings.Join(values, ", ")))
+		}
+		if s.Format == "date-time" && b.ValidateRFC3339Func != "" {
+			validators = append(validators, b.ValidateRFC3339Func)
+		}
+		if s.Pattern != "" {
+			validators = append(validators, fmt.Sprintf("validation.StringMatch(regexp.MustCompile(%s), \"\")",
+				quoteRegexp(s.Pattern)))
+		}
+		switch {
+		case s.MinLength != nil && s.MaxLength != nil:
+			validators = append(validators, fmt.Sprintf("validation.StringLenBetween(%d, %d)", *s.MinLength, *s.MaxLength))
+		case s.MinLength != nil && *s.MinLength == 1:
+			validators = append(validators, "validation.NoZeroValues")
+		case s.MinLength != nil:
+			validators = append(validators, fmt.Sprintf("validation.StringLenBetween(%d, math.MaxInt32)", *s.MinLength))
+		case s.MaxLength != nil:
+			validators = append(validators, fmt.Sprintf("validation.StringLenBetween(0, %d)", *s.MaxLength))
+		}
+	case "integer":
+		if len(s.Enum) > 0 {
+			values := make([]string, len(s.Enum), len(s.Enum))
+			for i, v := range s.Enum {
+				values[i] = fmt.Sprintf("%v", v)
+			}
+			validators = append(validators, fmt.Sprintf("validation.IntInSlice([]int{%s})",
+				strings.Join(values, ", ")))
+		}
+		switch {
+		case s.Minimum != nil && s.Maximum != nil:
+			validators = append(validators, fmt.Sprintf("validation.IntBetween(%d, %d)", int(*s.Minimum), int(*s.Maximum)))
+		case s.Minimum != nil:
+			validators = append(validators, fmt.Sprintf("validation.IntAtLeast(%d)", int(*s.Minimum)))
+		case s.Maximum != nil:
+			validators = append(validators, fmt.Sprintf("validation.IntAtMost(%d)", int(*s.Maximum)))
+		}
+	case "number":
+		switch {
+		case s.Minimum != nil && s.Maximum != nil:
+			validators = append(validators, fmt.Sprintf("validation.FloatBetween(%s, %s)",
+				formatFloat(*s.Minimum), formatFloat(*s.Maximum)))
+		case s.Minimum != nil:
+			validators = append(validators, fmt.Sprintf("validation.FloatBetween(%s, math.MaxFloat64)", formatFloat(*s.Minimum)))
+		case s.Maximum != nil:
+			validators = append(validators, fmt.Sprintf("validation.FloatBetween(-math.MaxFloat64, %s)", formatFloat(*s.Maximum)))
+		}
+	}
+
+	return combineValidators(validators)
+}
+
+func combineValidators(validators []string) string {
+	switch len(validators) {
+	case 0:
+		return ""
+	case 1:
+		return validators[0]
+	}
+	return "validation.All(\n" + strings.Join(validators, ",\n") + ",\n)"
+}
+
+// quoteRegexp prefers raw string literal, so the pattern stays readable
+func quoteRegexp(pattern string) string {
+	if strings.Contains(pattern, "`") {
+		return strconv.Quote(pattern)
+	}
+	return "`" + pattern + "`"
+}
+
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package schemagen
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+func TestSpecType_unmarshal(t *testing.T) {
+	cases := map[string]specType{
+		`{"type": "integer"}`:            "integer",
+		`{"type": ["null", "boolean"]}`:  "boolean",
+		`{"type": ["string", "number"]}`: "string",
+		`{}`:                             "",
+	}
+	for input, expected := range cases {
+		var s specSchema
+		err := json.Unmarshal([]byte(input), &s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Type != expected {
+			t.Fatalf("Expected %q for %s, given: %q", expected, input, s.Type)
+		}
+	}
+}
+
+func TestSpecSchema_validateFunc(t *testing.T) {
+	min, max := 1.5, 10.0
+	minLength := 2
+	cases := []struct {
+		Schema   *specSchema
+		Backend  *backends.Backend
+		Expected string
+	}{
+		{&specSchema{Type: "boolean"}, backends.HelperSchema, ""},
+		{&specSchema{Type: "number", Minimum: &min, Maximum: &max}, backends.HelperSchema, "validation.FloatBetween(1.5, 10.0)"},
+		{&specSchema{Type: "number", Minimum: &min}, backends.HelperSchema, "validation.FloatBetween(1.5, math.MaxFloat64)"},
+		{&specSchema{Type: "number", Maximum: &max}, backends.HelperSchema, "validation.FloatBetween(-math.MaxFloat64, 10.0)"},
+		{&specSchema{Type: "integer", Maximum: &max}, backends.HelperSchema, "validation.IntAtMost(10)"},
+		{&specSchema{Type: "string", MinLength: &minLength}, backends.HelperSchema, "validation.StringLenBetween(2, math.MaxInt32)"},
+		{&specSchema{Type: "string", Pattern: "^`x`$"}, backends.HelperSchema, "validation.StringMatch(regexp.MustCompile(\"^`x`$\"), \"\")"},
+		{&specSchema{Type: "string", Format: "date-time"}, backends.HelperSchema, "validation.ValidateRFC3339TimeString"},
+		{&specSchema{Type: "string", Format: "date-time"}, backends.PluginSDKv2, "validation.IsRFC3339Time"},
+	}
+	for _, tc := range cases {
+		given := tc.Schema.validateFunc(tc.Backend)
+		if given != tc.Expected {
+			t.Fatalf("Expected: %s\n\nGiven: %s\n", tc.Expected, given)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package schemagen
+
+import (
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// TagKey is the key of struct tags recognised by schemagen,
+// e.g. `terraform-gen:"default=80"`
+const TagKey = u.TagKey
@@ -0,0 +1,127 @@
+package schemagen
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	u "github.com/radeksimko/terraform-gen/internal/util"
+)
+
+// UnionKind describes how many fields of a union (one-of) struct may be set
+type UnionKind int
+
+const (
+	NotUnion UnionKind = iota
+	// AtMostOneOf allows at most one field to be set (ConflictsWith)
+	AtMostOneOf
+	// ExactlyOneOf requires exactly one field to be set (ConflictsWith & ExactlyOneOf)
+	ExactlyOneOf
+)
+
+// oneOfInDocs matches phrases like "Only one of its members may be specified."
+var oneOfInDocs = regexp.MustCompile("(?i)\\b(only|at most|exactly) one of\\b")
+
+// unionKind recognises union struct from (in order of precedence) the struct tag
+// of the field (oneof or exactlyoneof), Unions of the generator or the description
+func (g *SchemaGenerator) unionKind(structType reflect.Type, sf *reflect.StructField, docs string) UnionKind {
+	if sf != nil {
+		opts := u.TagOptions(sf)
+		if _, ok := opts["exactlyoneof"]; ok {
+			return ExactlyOneOf
+		}
+		if _, ok := opts["oneof"]; ok {
+			return AtMostOneOf
+		}
+	}
+	if kind, ok := g.Unions[u.DereferencePtrType(structType)]; ok {
+		return kind
+	}
+	m := oneOfInDocs.FindStringSubmatch(docs)
+	if m == nil {
+		return NotUnion
+	}
+	if strings.ToLower(m[1]) == "exactly" {
+		return ExactlyOneOf
+	}
+	return AtMostOneOf
+}
+
+// linkUnions sets ConflictsWith (and ExactlyOneOf, if supported by the backend)
+// of members of unions to full paths of other members, e.g. volume_source.0.host_path
+func (b block) linkUnions(prefix string, kind UnionKind, exactlyOneOf bool) {
+	if kind != NotUnion {
+		b.linkMembers(prefix, kind, exactlyOneOf)
+	}
+
+	for _, name := range b.sortedNames() {
+		f := b[name]
+		nested, ok := f.Elem.(block)
+		if !ok {
+			continue
+		}
+		if f.Schema.Type == schema.TypeList && f.Schema.MaxItems == 1 {
+			nested.linkUnions(prefix+name+".0.", f.Union, exactlyOneOf)
+		} else if f.Union != NotUnion {
+			log.Printf("Unable to link members of %q (items of %s cannot be referenced)", prefix+name, f.Schema.Type)
+		}
+	}
+}
+
+func (b block) linkMembers(prefix string, kind UnionKind, exactlyOneOf bool) {
+	members := make([]string, 0)
+	for _, name := range b.sortedNames() {
+		s := b[name].Schema
+		if s.Computed && !s.Optional {
+			continue
+		}
+		if s.Required {
+			log.Printf("Making %q optional (member of union)", prefix+name)
+			s.Required = false
+			s.Optional = true
+		}
+		members = append(members, name)
+	}
+
+	for _, name := range members {
+		f := b[name]
+		f.Schema.ConflictsWith = make([]string, 0)
+		for _, other := range members {
+			if other != name {
+				f.Schema.ConflictsWith = append(f.Schema.ConflictsWith, prefix+other)
+			}
+		}
+		if kind == ExactlyOneOf && exactlyOneOf {
+			f.ExactlyOneOf = make([]string, len(members), len(members))
+			for i, member := range members {
+				f.ExactlyOneOf[i] = prefix + member
+			}
+		}
+	}
+}
+
+func (b block) sortedNames() []string {
+	names := make([]string, 0, len(b))
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stringsLiteral renders slice of strings as Go literal, e.g. []string{"a", "b"}
+func stringsLiteral(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(values), len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return fmt.Sprintf("[]string{%s}", strings.Join(quoted, ", "))
+}
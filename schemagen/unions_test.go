@@ -0,0 +1,216 @@
+package schemagen
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/radeksimko/terraform-gen/backends"
+)
+
+type unionHostPath struct {
+	Path string
+}
+
+type unionSecretVolume struct {
+	SecretName string
+}
+
+type unionVolumeSource struct {
+	HostPath *unionHostPath
+	Secret   *unionSecretVolume
+}
+
+type unionVolume struct {
+	Name   string
+	Source unionVolumeSource
+}
+
+type unionPodSpec struct {
+	Volume       unionVolume
+	Volumes      []unionVolume
+	Probe        unionProbe `terraform-gen:"exactlyoneof"`
+	DNSConfig    unionDNSConfig
+	Hostname     string
+	RestartCount int
+}
+
+type unionProbe struct {
+	Exec    string
+	TCPPort int
+	Status  string
+}
+
+type unionDNSConfig struct {
+	Nameservers string
+	Searches    string
+}
+
+func unionsGenerator() *SchemaGenerator {
+	return &SchemaGenerator{
+		DocsFunc: func(_struct interface{}, sf *reflect.StructField) string {
+			if sf.Name == "DNSConfig" {
+				return "DNS config. Only one of its members may be specified."
+			}
+			return ""
+		},
+		FilterFunc: func(iface interface{}, sf *reflect.StructField, k reflect.Kind, s *schema.Schema) (reflect.Kind, bool) {
+			switch sf.Name {
+			case "Status":
+				s.Computed = true
+			case "Exec":
+				s.Required = true
+			default:
+				s.Optional = true
+			}
+			return k, true
+		},
+		Unions: map[reflect.Type]UnionKind{
+			reflect.TypeOf(unionVolumeSource{}): AtMostOneOf,
+		},
+	}
+}
+
+func TestGenerateField_unions(t *testing.T) {
+	g := unionsGenerator()
+	fields := g.FromStruct(&unionPodSpec{})
+
+	expectedVolume := `{
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"name": {
+Type: schema.TypeString,
+Optional: true,
+},
+"source": {
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"host_path": {
+Type: schema.TypeList,
+Optional: true,
+ConflictsWith: []string{"volume.0.source.0.secret"},
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"path": {
+Type: schema.TypeString,
+Optional: true,
+},
+},
+},
+},
+"secret": {
+Type: schema.TypeList,
+Optional: true,
+ConflictsWith: []string{"volume.0.source.0.host_path"},
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"secret_name": {
+Type: schema.TypeString,
+Optional: true,
+},
+},
+},
+},
+},
+},
+},
+},
+},
+}`
+	if fields["volume"] != expectedVolume {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedVolume, fields["volume"])
+	}
+
+	expectedProbe := `{
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"exec": {
+Type: schema.TypeString,
+Optional: true,
+ConflictsWith: []string{"probe.0.tcp_port"},
+},
+"status": {
+Type: schema.TypeString,
+Computed: true,
+},
+"tcp_port": {
+Type: schema.TypeInt,
+Optional: true,
+ConflictsWith: []string{"probe.0.exec"},
+},
+},
+},
+}`
+	if fields["probe"] != expectedProbe {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedProbe, fields["probe"])
+	}
+
+	s := g.SchemaFromStruct(&unionPodSpec{})
+	dnsConfig := s["dns_config"].Elem.(*schema.Resource).Schema
+	expectedConflicts := []string{"dns_config.0.searches"}
+	if given := dnsConfig["nameservers"].ConflictsWith; !reflect.DeepEqual(given, expectedConflicts) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedConflicts, given)
+	}
+
+	// items of lists & sets cannot be referenced
+	volumes := s["volumes"].Elem.(*schema.Resource).Schema
+	source := volumes["source"].Elem.(*schema.Resource).Schema
+	if len(source["host_path"].ConflictsWith) > 0 {
+		t.Fatalf("Expected no ConflictsWith, given: %q", source["host_path"].ConflictsWith)
+	}
+}
+
+func TestGenerateField_exactlyOneOf(t *testing.T) {
+	g := unionsGenerator()
+	g.Backend = backends.PluginSDKv2
+	fields := g.FromStruct(&unionPodSpec{})
+
+	expectedProbe := `{
+Type: schema.TypeList,
+Optional: true,
+MaxItems: 1,
+Elem: &schema.Resource{
+Schema: map[string]*schema.Schema{
+"exec": {
+Type: schema.TypeString,
+Optional: true,
+ConflictsWith: []string{"probe.0.tcp_port"},
+ExactlyOneOf: []string{"probe.0.exec", "probe.0.tcp_port"},
+},
+"status": {
+Type: schema.TypeString,
+Computed: true,
+},
+"tcp_port": {
+Type: schema.TypeInt,
+Optional: true,
+ConflictsWith: []string{"probe.0.exec"},
+ExactlyOneOf: []string{"probe.0.exec", "probe.0.tcp_port"},
+},
+},
+},
+}`
+	if fields["probe"] != expectedProbe {
+		t.Fatalf("Expected: %s\n\nGiven: %s\n", expectedProbe, fields["probe"])
+	}
+}
+
+func TestGenerateField_rootUnion(t *testing.T) {
+	g := unionsGenerator()
+	s := g.SchemaFromStruct(&unionVolumeSource{})
+	expectedConflicts := []string{"secret"}
+	if given := s["host_path"].ConflictsWith; !reflect.DeepEqual(given, expectedConflicts) {
+		t.Fatalf("Expected: %q\n\nGiven: %q\n", expectedConflicts, given)
+	}
+}